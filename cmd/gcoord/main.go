@@ -31,12 +31,7 @@ func main() {
 一个功能强大的地理坐标转换命令行工具，支持多种坐标系之间的转换。
 
 支持的坐标系:
-  %s  WGS84: 世界大地坐标系，GPS原始坐标
-  %s  GCJ02: 国测局坐标系，中国标准坐标系  
-  %s  BD09: 百度坐标系
-  %s  BD09MC: 百度墨卡托投影坐标系
-  %s  EPSG3857: Web墨卡托投影坐标系
-
+%s
 使用示例:
   %s 转换单个坐标点
   %s 转换JSON格式的坐标
@@ -44,11 +39,7 @@ func main() {
 
 更多信息请使用子命令的 --help 参数查看。`,
 			bold("🗺️"),
-			yellow("•"),
-			yellow("•"),
-			yellow("•"),
-			yellow("•"),
-			yellow("•"),
+			crsHelp(),
 			green("gcoord convert -from WGS84 -to GCJ02 -lon 116.397 -lat 39.908"),
 			green(`gcoord convert -from WGS84 -to BD09 -json '{"type":"Point","coordinates":[116.397,39.908]}'`),
 			green("gcoord list"),
@@ -81,18 +72,38 @@ var convertCmd = &cobra.Command{
   %s 经纬度坐标: -lon <经度> -lat <纬度>
   %s JSON格式: -json '<JSON坐标>'
   %s GeoJSON对象: Point, LineString, Polygon, Feature, FeatureCollection
+  %s GeoJSON文件: --input <文件> [--output <文件>]，流式转换大文件
+  %s 行式GeoJSON: --format ndjson|geojsonseq，从 --input 或标准输入逐条读取
+  %s CSV: --csv [--lon-col <列>] [--lat-col <列>] [--append]
+  %s WKT/WKB: --wkt '<WKT 或十六进制 WKB>'，保留 Z/M 维度
+  %s 编码折线: --format polyline [--precision 5|6]，每行一条折线
 
 示例:
   %s 转换单个坐标点
   %s 转换JSON格式的坐标
-  %s 转换GeoJSON Feature`,
+  %s 转换GeoJSON Feature
+  %s 流式转换GeoJSON文件
+  %s 转换NDJSON
+  %s 转换CSV并追加结果列
+  %s 转换WKT
+  %s 转换高德路径规划返回的编码折线`,
 		bold("🔄"),
 		yellow("•"),
 		yellow("•"),
 		yellow("•"),
+		yellow("•"),
+		yellow("•"),
+		yellow("•"),
+		yellow("•"),
+		yellow("•"),
 		green("gcoord convert -from WGS84 -to GCJ02 -lon 116.397 -lat 39.908"),
 		green(`gcoord convert -from WGS84 -to BD09 -json '{"type":"Point","coordinates":[116.397,39.908]}'`),
 		green(`gcoord convert -from GCJ02 -to EPSG3857 -json '{"type":"Feature","geometry":{"type":"Point","coordinates":[116.397,39.908]}}'`),
+		green("gcoord convert -from WGS84 -to GCJ02 --input input.geojson --output output.geojson"),
+		green("cat features.ndjson | gcoord convert -from WGS84 -to BD09 --format ndjson"),
+		green("gcoord convert -from BD09 -to WGS84 --csv --input poi.csv --lon-col lng --lat-col lat --append"),
+		green(`gcoord convert -from WGS84 -to GCJ02 --wkt 'LINESTRING Z (116.397 39.908 50, 116.404 39.915 60)'`),
+		green("echo '_p~iF~ps|U_ulLnnqC_mqNvxq`@' | gcoord convert -from GCJ02 -to WGS84 --format polyline"),
 	),
 	Run: runConvert,
 }
@@ -111,12 +122,27 @@ var listCmd = &cobra.Command{
 
 func init() {
 	// convert 命令参数
-	convertCmd.Flags().StringP("from", "f", "", "源坐标系 (必需)")
-	convertCmd.Flags().StringP("to", "t", "", "目标坐标系 (必需)")
+	convertCmd.Flags().StringP("from", "f", "", "源坐标系，支持别名与 EPSG 代码 (必需)")
+	convertCmd.Flags().StringP("to", "t", "", "目标坐标系，支持别名与 EPSG 代码 (必需)")
 	convertCmd.Flags().Float64("lon", 0, "经度")
 	convertCmd.Flags().Float64("lat", 0, "纬度")
 	convertCmd.Flags().StringP("json", "j", "", "JSON格式的坐标输入")
+	convertCmd.Flags().String("wkt", "", "WKT/EWKT 或十六进制 WKB/EWKB 输入，输出格式与输入相同")
 	convertCmd.Flags().BoolP("verbose", "v", false, "显示详细信息")
+	convertCmd.Flags().Int("workers", 1, "并行转换 FeatureCollection 使用的 goroutine 数")
+	convertCmd.Flags().StringP("input", "i", "", "输入 GeoJSON 文件，- 表示标准输入，流式转换大文件")
+	convertCmd.Flags().StringP("output", "o", "", "输出文件，默认为标准输出")
+	convertCmd.Flags().String("format", "", "流式输入格式: geojson, ndjson, geojsonseq, csv, polyline，未指定 --input 时读取标准输入")
+	convertCmd.Flags().Bool("csv", false, "转换带表头的 CSV，等同于 --format csv")
+	convertCmd.Flags().String("lon-col", "", "CSV 经度（或 x）列名或从 0 开始的列序号，默认自动识别")
+	convertCmd.Flags().String("lat-col", "", "CSV 纬度（或 y）列名或从 0 开始的列序号，默认自动识别")
+	convertCmd.Flags().Bool("append", false, "CSV 保留原坐标列，将转换结果追加为新列")
+	convertCmd.Flags().Int("precision", gcoord.DefaultPolylinePrecision, "编码折线的坐标小数位数，常用 5 或 6")
+	convertCmd.Flags().Bool("strict", false, "拒绝 NaN、Inf 与超出经纬度范围的坐标")
+	convertCmd.Flags().String("out-of-china", "pass", "WGS84↔GCJ02 转换时中国境外坐标的处理方式: pass, error")
+	convertCmd.Flags().String("bounds", "clamp", "转换到 EPSG3857 超出 Web 墨卡托范围时的处理方式: clamp, error")
+	convertCmd.Flags().Bool("2d", false, "丢弃 Z、M 等额外维度，只输出 [x, y]")
+	convertCmd.Flags().Bool("bbox", false, "为 GeoJSON 根对象和每个 Feature 补充 bbox")
 
 	// 标记必需参数
 	convertCmd.MarkFlagRequired("from")
@@ -130,26 +156,96 @@ func runConvert(cmd *cobra.Command, args []string) {
 	lon, _ := cmd.Flags().GetFloat64("lon")
 	lat, _ := cmd.Flags().GetFloat64("lat")
 	jsonInput, _ := cmd.Flags().GetString("json")
+	wktInput, _ := cmd.Flags().GetString("wkt")
 	verbose, _ := cmd.Flags().GetBool("verbose")
-
-	// 验证坐标系
-	if !isValidCRS(fromCRS) {
+	workers, _ := cmd.Flags().GetInt("workers")
+	inputFile, _ := cmd.Flags().GetString("input")
+	outputFile, _ := cmd.Flags().GetString("output")
+	format, _ := cmd.Flags().GetString("format")
+	csvMode, _ := cmd.Flags().GetBool("csv")
+	lonCol, _ := cmd.Flags().GetString("lon-col")
+	latCol, _ := cmd.Flags().GetString("lat-col")
+	appendCols, _ := cmd.Flags().GetBool("append")
+	precision, _ := cmd.Flags().GetInt("precision")
+	strict, _ := cmd.Flags().GetBool("strict")
+	outOfChina, _ := cmd.Flags().GetString("out-of-china")
+	bounds, _ := cmd.Flags().GetString("bounds")
+	drop2D, _ := cmd.Flags().GetBool("2d")
+	addBBox, _ := cmd.Flags().GetBool("bbox")
+
+	// 验证坐标系，支持别名与 EPSG 代码
+	from, err := gcoord.ParseCRS(fromCRS)
+	if err != nil {
 		fmt.Printf("%s 错误: 无效的源坐标系 '%s'\n", red("❌"), fromCRS)
 		showValidCRS()
 		os.Exit(1)
 	}
 
-	if !isValidCRS(toCRS) {
+	to, err := gcoord.ParseCRS(toCRS)
+	if err != nil {
 		fmt.Printf("%s 错误: 无效的目标坐标系 '%s'\n", red("❌"), toCRS)
 		showValidCRS()
 		os.Exit(1)
 	}
+	fromCRS, toCRS = string(from), string(to)
+	opts := gcoord.TransformOptions{Workers: workers, Strict: strict, DropExtraDims: drop2D, AddBBox: addBBox}
+	switch outOfChina {
+	case "pass":
+	case "error":
+		opts.OutOfChina = gcoord.OutOfChinaError
+	default:
+		fmt.Printf("%s 错误: --out-of-china 只能为 pass 或 error\n", red("❌"))
+		os.Exit(1)
+	}
+	switch bounds {
+	case "clamp":
+	case "error":
+		opts.Bounds = gcoord.BoundsError
+	default:
+		fmt.Printf("%s 错误: --bounds 只能为 clamp 或 error\n", red("❌"))
+		os.Exit(1)
+	}
+
+	// 文件或标准输入：流式转换
+	if csvMode {
+		format = formatCSV
+	}
+	if inputFile != "" || format != "" {
+		switch format {
+		case "", formatGeoJSON, formatNDJSON, formatGeoJSONSeq, formatCSV, formatPolyline:
+		default:
+			fmt.Printf("%s 错误: 不支持的格式 '%s'\n", red("❌"), format)
+			os.Exit(1)
+		}
+		err := runStream(streamConfig{
+			inputFile:  inputFile,
+			outputFile: outputFile,
+			format:     format,
+			from:       from,
+			to:         to,
+			opts:       opts,
+			csv: gcoord.CSVOptions{
+				LonColumn: lonCol,
+				LatColumn: latCol,
+				Append:    appendCols,
+				Options:   opts,
+			},
+			precision: precision,
+		})
+		if err != nil {
+			fmt.Printf("%s 转换错误: %v\n", red("❌"), err)
+			os.Exit(1)
+		}
+		return
+	}
 
 	// 处理输入
 	var input interface{}
-	var err error
 
-	if jsonInput != "" {
+	if wktInput != "" {
+		// WKT/WKB 输入，按字符串转换
+		input = wktInput
+	} else if jsonInput != "" {
 		// JSON 输入
 		input, err = parseJSONInput(jsonInput)
 		if err != nil {
@@ -167,7 +263,7 @@ func runConvert(cmd *cobra.Command, args []string) {
 	}
 
 	// 执行转换
-	result, err := gcoord.Transform(input, gcoord.CRSTypes(fromCRS), gcoord.CRSTypes(toCRS))
+	result, err := gcoord.TransformWithOptions(input, from, to, opts)
 	if err != nil {
 		fmt.Printf("%s 转换错误: %v\n", red("❌"), err)
 		os.Exit(1)
@@ -180,69 +276,35 @@ func runConvert(cmd *cobra.Command, args []string) {
 func runList(cmd *cobra.Command, args []string) {
 	fmt.Printf("%s 支持的坐标系\n\n", bold("📋"))
 
-	crsList := []struct {
-		name        string
-		description string
-		aliases     []string
-		precision   string
-	}{
-		{
-			name:        "WGS84",
-			description: "世界大地坐标系，GPS原始坐标",
-			aliases:     []string{"WGS1984", "EPSG4326"},
-			precision:   "约 1e-5 度 (约 1 米)",
-		},
-		{
-			name:        "GCJ02",
-			description: "国测局坐标系，中国标准坐标系",
-			aliases:     []string{"AMap"},
-			precision:   "约 1e-5 度 (约 1 米)",
-		},
-		{
-			name:        "BD09",
-			description: "百度坐标系",
-			aliases:     []string{"BD09LL", "Baidu", "BMap"},
-			precision:   "约 1e-5 度 (约 1 米)",
-		},
-		{
-			name:        "BD09MC",
-			description: "百度墨卡托投影坐标系",
-			aliases:     []string{"BD09Meter"},
-			precision:   "约 1 米",
-		},
-		{
-			name:        "EPSG3857",
-			description: "Web墨卡托投影坐标系",
-			aliases:     []string{"EPSG900913", "EPSG102100", "WebMercator", "WM"},
-			precision:   "约 1 米",
-		},
-	}
-
-	for _, crs := range crsList {
-		fmt.Printf("%s %s\n", blue("📍"), bold(crs.name))
-		fmt.Printf("  描述: %s\n", crs.description)
-		if len(crs.aliases) > 0 {
-			fmt.Printf("  别名: %s\n", strings.Join(crs.aliases, ", "))
+	for _, entry := range listCRSEntries() {
+		crs := entry.info
+		fmt.Printf("%s %s\n", blue("📍"), bold(entry.name()))
+		if desc := entry.description(); desc != "" {
+			fmt.Printf("  描述: %s\n", desc)
+		}
+		if len(crs.Aliases) > 0 && entry.family == nil {
+			fmt.Printf("  别名: %s\n", strings.Join(crs.Aliases, ", "))
+		}
+		fmt.Printf("  精度: %s\n", cyan(formatPrecision(crs)))
+		if _, err := gcoord.ConversionPath(crs.Name, gcoord.WGS84); err != nil {
+			fmt.Printf("  %s 暂无转换路径，不能与其他坐标系转换\n", yellow("⚠"))
 		}
-		fmt.Printf("  精度: %s\n", cyan(crs.precision))
 		fmt.Println()
 	}
 
 	fmt.Printf("%s 转换路径支持:\n", bold("🔄"))
-	fmt.Printf("  %s 所有坐标系之间都可以相互转换\n", green("✓"))
+	fmt.Printf("  %s 有转换路径的坐标系之间都可以相互转换\n", green("✓"))
 	fmt.Printf("  %s 自动选择最优转换路径\n", green("✓"))
 	fmt.Printf("  %s 支持链式转换 (如: WGS84 → GCJ02 → BD09)\n", green("✓"))
 }
 
-func isValidCRS(crs string) bool {
-	validCRS := map[string]bool{
-		"WGS84":    true,
-		"GCJ02":    true,
-		"BD09":     true,
-		"BD09MC":   true,
-		"EPSG3857": true,
+// formatPrecision 格式化坐标系的转换精度
+func formatPrecision(crs gcoord.CRSInfo) string {
+	if crs.Projected {
+		return fmt.Sprintf("约 %g 米", crs.Precision)
 	}
-	return validCRS[crs]
+	// 1 度约 111 公里
+	return fmt.Sprintf("约 %g 度 (约 %g 米)", crs.Precision, crs.Precision*1e5)
 }
 
 func parseJSONInput(jsonStr string) (interface{}, error) {
@@ -259,6 +321,13 @@ func showResult(input, result interface{}, fromCRS, toCRS string, verbose bool)
 		// 显示输入信息
 		fmt.Printf("\n%s 源坐标系: %s\n", blue("📍"), magenta(fromCRS))
 		fmt.Printf("%s 目标坐标系: %s\n", blue("🎯"), magenta(toCRS))
+		if path, err := gcoord.ConversionPath(gcoord.CRSTypes(fromCRS), gcoord.CRSTypes(toCRS)); err == nil {
+			names := make([]string, len(path))
+			for i, crs := range path {
+				names[i] = string(crs)
+			}
+			fmt.Printf("%s 转换路径: %s\n", blue("🔄"), strings.Join(names, " → "))
+		}
 
 		// 显示输入坐标
 		fmt.Printf("\n%s 输入坐标:\n", yellow("📥"))
@@ -273,6 +342,16 @@ func showResult(input, result interface{}, fromCRS, toCRS string, verbose bool)
 			fmt.Printf("%.6f,%.6f\n", pos[0], pos[1])
 			return
 		}
+		// WKT/WKB 输入原样输出字符串
+		if str, ok := result.(string); ok {
+			fmt.Println(str)
+			return
+		}
+		// JSON 输入输出紧凑 JSON
+		if b, err := json.Marshal(result); err == nil {
+			fmt.Println(string(b))
+		}
+		return
 	}
 
 	// 如果是简单坐标点，显示格式化输出
@@ -284,10 +363,12 @@ func showResult(input, result interface{}, fromCRS, toCRS string, verbose bool)
 
 			// 显示精度信息
 			fmt.Printf("\n%s 转换精度:\n", blue("🎯"))
-			if toCRS == "EPSG3857" || toCRS == "BD09MC" {
-				fmt.Printf("  投影坐标精度: 约 1 米\n")
-			} else {
-				fmt.Printf("  经纬度精度: 约 1e-5 度 (约 1 米)\n")
+			if info, ok := gcoord.LookupCRS(gcoord.CRSTypes(toCRS)); ok {
+				if info.Projected {
+					fmt.Printf("  投影坐标精度: %s\n", formatPrecision(info))
+				} else {
+					fmt.Printf("  经纬度精度: %s\n", formatPrecision(info))
+				}
 			}
 
 			fmt.Printf("\n%s\n", strings.Repeat("=", 50))
@@ -329,6 +410,87 @@ func showCoordinate(coord interface{}, prefix string) {
 }
 
 func showValidCRS() {
-	validCRS := []string{"WGS84", "GCJ02", "BD09", "BD09MC", "EPSG3857"}
+	var validCRS []string
+	for _, entry := range listCRSEntries() {
+		validCRS = append(validCRS, entry.name())
+	}
 	fmt.Printf("支持的坐标系: %s\n", strings.Join(validCRS, ", "))
 }
+
+// crsFamily 按带号批量注册的一组投影带，列表中合并为一项
+type crsFamily struct {
+	// prefixes 组内坐标系名称的前缀
+	prefixes []string
+	pattern  string
+	desc     string
+}
+
+var crsFamilies = []*crsFamily{
+	{
+		prefixes: []string{"UTM_ZONE_"},
+		pattern:  "UTM_ZONE_{1-60}{N|S}",
+		desc:     "WGS84 UTM 投影，南北半球各 60 带，如 UTM_ZONE_50N（EPSG:32601–32660、32701–32760）",
+	},
+	{
+		prefixes: []string{"CGCS2000_GK_", "CGCS2000_3GK_"},
+		pattern:  "CGCS2000_{GK|3GK}_{ZONE_<带号>|CM_<中央经线>E}",
+		desc:     "CGCS2000 高斯-克吕格投影，6 度带 GK 第 13–23 带、3 度带 3GK 第 25–45 带，ZONE 东坐标含带号、CM 不含，如 CGCS2000_3GK_ZONE_39（EPSG:4491–4554）",
+	},
+}
+
+// crsEntry 坐标系列表中的一项，family 不为 nil 时代表一组投影带，info 为其中第一个
+type crsEntry struct {
+	info   gcoord.CRSInfo
+	family *crsFamily
+	count  int
+}
+
+// listCRSEntries 按注册顺序列出坐标系，同一组投影带合并为一项
+func listCRSEntries() []crsEntry {
+	var entries []crsEntry
+	index := map[*crsFamily]int{}
+	for _, info := range gcoord.ListCRS() {
+		var family *crsFamily
+		for _, f := range crsFamilies {
+			for _, prefix := range f.prefixes {
+				if strings.HasPrefix(string(info.Name), prefix) {
+					family = f
+				}
+			}
+		}
+		if family == nil {
+			entries = append(entries, crsEntry{info: info})
+			continue
+		}
+		if i, ok := index[family]; ok {
+			entries[i].count++
+			continue
+		}
+		index[family] = len(entries)
+		entries = append(entries, crsEntry{info: info, family: family, count: 1})
+	}
+	return entries
+}
+
+func (e crsEntry) name() string {
+	if e.family != nil {
+		return e.family.pattern
+	}
+	return string(e.info.Name)
+}
+
+func (e crsEntry) description() string {
+	if e.family != nil {
+		return fmt.Sprintf("%s，共 %d 个", e.family.desc, e.count)
+	}
+	return e.info.Description
+}
+
+// crsHelp 根命令帮助中的坐标系列表
+func crsHelp() string {
+	var b strings.Builder
+	for _, entry := range listCRSEntries() {
+		fmt.Fprintf(&b, "  %s  %s: %s\n", yellow("•"), entry.name(), entry.description())
+	}
+	return b.String()
+}
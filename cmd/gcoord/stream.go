@@ -0,0 +1,146 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/bytebotgo/gcoord-go/gcoord"
+)
+
+// 流式输入格式
+const (
+	formatGeoJSON    = "geojson"
+	formatNDJSON     = "ndjson"
+	formatGeoJSONSeq = "geojsonseq"
+	formatCSV        = "csv"
+	formatPolyline   = "polyline"
+)
+
+// streamConfig 流式转换的参数
+type streamConfig struct {
+	// inputFile 为空或 - 时读取标准输入
+	inputFile string
+	// outputFile 为空或 - 时写入标准输出
+	outputFile string
+	format     string
+	from, to   gcoord.CRSTypes
+	opts       gcoord.TransformOptions
+	csv        gcoord.CSVOptions
+	// precision 编码折线的坐标小数位数
+	precision int
+}
+
+// runStream 流式转换文件或标准输入
+func runStream(cfg streamConfig) error {
+	in, err := openInput(cfg.inputFile)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := openOutput(cfg.outputFile)
+	if err != nil {
+		return err
+	}
+
+	switch cfg.format {
+	case formatNDJSON, formatGeoJSONSeq:
+		err = runLines(in, out, cfg.format, cfg.from, cfg.to, cfg.opts)
+	case formatCSV:
+		err = gcoord.TransformCSV(in, out, cfg.from, cfg.to, cfg.csv)
+	case formatPolyline:
+		opts := cfg.opts
+		opts.PolylinePrecision = cfg.precision
+		err = runPolylines(in, out, cfg.from, cfg.to, opts)
+	default:
+		err = gcoord.TransformStreamWithOptions(in, out, cfg.from, cfg.to, cfg.opts)
+		if _, ok := out.(nopWriteCloser); ok && err == nil {
+			// 标准输出以换行结束
+			_, err = io.WriteString(out, "\n")
+		}
+	}
+	if cerr := out.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// runLines 逐条转换行式 GeoJSON，单条记录的错误输出到标准错误后继续处理
+func runLines(in io.Reader, out io.Writer, format string, from, to gcoord.CRSTypes, opts gcoord.TransformOptions) error {
+	seqFormat := gcoord.FormatNDJSON
+	if format == formatGeoJSONSeq {
+		seqFormat = gcoord.FormatGeoJSONSeq
+	}
+
+	lineErrs, err := gcoord.TransformLinesWithOptions(in, out, from, to, seqFormat, opts)
+	for _, le := range lineErrs {
+		fmt.Fprintf(os.Stderr, "%s %v\n", red("❌"), le)
+	}
+	if err != nil {
+		return err
+	}
+	if len(lineErrs) > 0 {
+		return fmt.Errorf("%d 条记录转换失败", len(lineErrs))
+	}
+	return nil
+}
+
+// runPolylines 逐行转换编码折线，空行原样输出，出错的行输出到标准错误后继续处理
+func runPolylines(in io.Reader, out io.Writer, from, to gcoord.CRSTypes, opts gcoord.TransformOptions) error {
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 64*1024), 64*1024*1024)
+	w := bufio.NewWriter(out)
+	failed := 0
+
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text != "" {
+			res, err := gcoord.TransformWithOptions(gcoord.Polyline(text), from, to, opts)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "%s 第 %d 行: %v\n", red("❌"), line, err)
+				failed++
+				continue
+			}
+			text = string(res)
+		}
+		if _, err := fmt.Fprintln(w, text); err != nil {
+			return err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d 条折线转换失败", failed)
+	}
+	return nil
+}
+
+// openInput 打开输入文件，空或 - 表示标准输入
+func openInput(name string) (io.ReadCloser, error) {
+	if name == "" || name == "-" {
+		return io.NopCloser(os.Stdin), nil
+	}
+	return os.Open(name)
+}
+
+// openOutput 创建输出文件，空或 - 表示标准输出
+func openOutput(name string) (io.WriteCloser, error) {
+	if name == "" || name == "-" {
+		return nopWriteCloser{os.Stdout}, nil
+	}
+	return os.Create(name)
+}
+
+// nopWriteCloser 关闭时不做任何操作，用于标准输出
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error { return nil }
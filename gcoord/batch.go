@@ -0,0 +1,93 @@
+package gcoord
+
+import (
+	"fmt"
+)
+
+// TransformFlat 原地转换交错存储的坐标数组，如 [lon0, lat0, lon1, lat1, ...]。
+//
+// dim 为每个坐标的维数（>=2），仅转换前两维，额外维度（如高程）保持不变。
+// 转换路径由内置或 RegisterPointConverter 注册的转换函数组成时，
+// 不会为每个点分配内存，适合百万级坐标的批量转换。
+//
+// 示例：
+//
+//	coords := []float64{116.397, 39.908, 121.473, 31.230}
+//	err := TransformFlat(coords, 2, WGS84, GCJ02)
+func TransformFlat(coords []float64, dim int, crsFrom, crsTo CRSTypes) error {
+	if dim < 2 {
+		return &TransformError{
+			Type:    ErrInvalidInput,
+			Message: fmt.Sprintf("坐标维数必须>=2: %d", dim),
+			Details: map[string]interface{}{
+				"dim": dim,
+			},
+		}
+	}
+	if len(coords)%dim != 0 {
+		return &TransformError{
+			Type:    ErrInvalidInput,
+			Message: fmt.Sprintf("坐标数组长度 %d 不是维数 %d 的整数倍", len(coords), dim),
+			Details: map[string]interface{}{
+				"length": len(coords),
+				"dim":    dim,
+			},
+		}
+	}
+
+	step, err := batchStep(crsFrom, crsTo)
+	if err != nil || step == nil {
+		return err
+	}
+	for i := 0; i+1 < len(coords); i += dim {
+		if coords[i], coords[i+1], err = step(coords[i], coords[i+1], defaultOptions); err != nil {
+			return withIndex(err, i/dim)
+		}
+	}
+	return nil
+}
+
+// TransformPoints 原地转换 [][2]float64 形式的坐标数组，
+// 出错时停止，错误的 Details["index"] 为出错的点序号
+func TransformPoints(points [][2]float64, crsFrom, crsTo CRSTypes) error {
+	step, err := batchStep(crsFrom, crsTo)
+	if err != nil || step == nil {
+		return err
+	}
+	for i := range points {
+		if points[i][0], points[i][1], err = step(points[i][0], points[i][1], defaultOptions); err != nil {
+			return withIndex(err, i)
+		}
+	}
+	return nil
+}
+
+// batchStep 验证坐标系并获取批量转换使用的转换函数，
+// 源与目标相同时返回 nil
+func batchStep(crsFrom, crsTo CRSTypes) (pointStep, error) {
+	crsFrom, err := validateCRS(crsFrom)
+	if err != nil {
+		return nil, err
+	}
+	crsTo, err = validateCRS(crsTo)
+	if err != nil {
+		return nil, err
+	}
+	if crsFrom == crsTo {
+		return nil, nil
+	}
+
+	step := getStep(crsFrom, crsTo)
+	if step == nil {
+		return nil, ErrNoConverter(crsFrom, crsTo)
+	}
+	return step, nil
+}
+
+// withIndex 在转换错误的 Details 中记录出错坐标的序号
+func withIndex(err error, index int) error {
+	if te, ok := err.(*TransformError); ok && te.Details != nil {
+		te.Details["index"] = index
+	}
+	return err
+}
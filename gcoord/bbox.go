@@ -0,0 +1,149 @@
+package gcoord
+
+import (
+	"fmt"
+	"math"
+)
+
+// bounds 二维外包矩形，ok 为 false 时表示尚未包含任何坐标
+type bounds struct {
+	minX, minY, maxX, maxY float64
+	ok                     bool
+}
+
+func (b *bounds) add(x, y float64) {
+	if math.IsNaN(x) || math.IsNaN(y) {
+		return
+	}
+	if !b.ok {
+		*b = bounds{x, y, x, y, true}
+		return
+	}
+	b.minX = min(b.minX, x)
+	b.minY = min(b.minY, y)
+	b.maxX = max(b.maxX, x)
+	b.maxY = max(b.maxY, y)
+}
+
+// extent 将 GeoJSON 对象或坐标数组中的所有坐标加入外包矩形
+func (b *bounds) extent(obj any) {
+	switch t := obj.(type) {
+	case map[string]any:
+		switch t["type"] {
+		case "FeatureCollection":
+			b.extent(t["features"])
+		case "Feature":
+			b.extent(t["geometry"])
+		case "GeometryCollection":
+			b.extent(t["geometries"])
+		default:
+			b.coords(t["coordinates"])
+		}
+	case []any:
+		for _, v := range t {
+			b.extent(v)
+		}
+	}
+}
+
+// coords 将坐标数组中的所有坐标加入外包矩形
+func (b *bounds) coords(coords any) {
+	c, ok := coords.([]any)
+	if !ok {
+		return
+	}
+	if len(c) >= 2 {
+		if x, ok := c[0].(float64); ok {
+			if y, ok := c[1].(float64); ok {
+				b.add(x, y)
+				return
+			}
+		}
+	}
+	for _, v := range c {
+		b.coords(v)
+	}
+}
+
+// apply 按外包矩形更新 bbox 成员，保持原有的维数：
+// 四元素为 [minX, minY, maxX, maxY]，六元素时 Z 的范围保持不变；
+// 没有 bbox 时新建四元素 bbox，其他长度的 bbox 不做修改
+func (b *bounds) apply(obj map[string]any) {
+	if !b.ok {
+		return
+	}
+	switch old, _ := obj["bbox"].([]any); len(old) {
+	case 0:
+		if _, exists := obj["bbox"]; !exists {
+			obj["bbox"] = []any{b.minX, b.minY, b.maxX, b.maxY}
+		}
+	case 4:
+		obj["bbox"] = []any{b.minX, b.minY, b.maxX, b.maxY}
+	case 6:
+		obj["bbox"] = []any{b.minX, b.minY, old[2], b.maxX, b.maxY, old[5]}
+	}
+}
+
+// updateMembers 转换对象的坐标后，重新计算已有的 bbox（AddBBox 时为 Feature 补充 bbox），
+// 并将 crs 成员更新为目标坐标系
+func (gt *geoJSONTransformer) updateMembers(obj map[string]any) {
+	if _, ok := obj["bbox"]; ok || (gt.opts.AddBBox && obj["type"] == "Feature") {
+		var b bounds
+		b.extent(obj)
+		b.apply(obj)
+	}
+	if _, ok := obj["crs"]; ok {
+		obj["crs"] = crsMember(gt.to)
+	}
+}
+
+// rootBBox AddBBox 时为文档的根对象补充 bbox
+func (gt *geoJSONTransformer) rootBBox(obj any) {
+	if m, ok := obj.(map[string]any); ok && gt.opts.AddBBox {
+		if _, exists := m["bbox"]; !exists {
+			var b bounds
+			b.extent(m)
+			b.apply(m)
+		}
+	}
+}
+
+// transformBBox 转换 bbox 的四个角点并重新取外包矩形，用于无法根据坐标重新计算的情况
+func (gt *geoJSONTransformer) transformBBox(bbox []any) ([]any, error) {
+	n := len(bbox)
+	if n != 4 && n != 6 {
+		return bbox, nil
+	}
+	minX, minY := toFloat(bbox[0]), toFloat(bbox[1])
+	maxX, maxY := toFloat(bbox[n/2]), toFloat(bbox[n/2+1])
+	var b bounds
+	for _, corner := range [4][2]float64{{minX, minY}, {minX, maxY}, {maxX, minY}, {maxX, maxY}} {
+		x, y, err := gt.point(corner[0], corner[1])
+		if err != nil {
+			return nil, withPath(err, ".bbox")
+		}
+		b.add(x, y)
+	}
+	if !b.ok {
+		return bbox, nil
+	}
+	if n == 6 {
+		return []any{b.minX, b.minY, bbox[2], b.maxX, b.maxY, bbox[5]}, nil
+	}
+	return []any{b.minX, b.minY, b.maxX, b.maxY}, nil
+}
+
+// crsMember 返回坐标系对应的 GeoJSON 2008 命名 crs 成员：
+// WGS84 使用 OGC CRS84，有 EPSG 代码的使用 EPSG URN，其余使用坐标系名称
+func crsMember(crs CRSTypes) map[string]any {
+	name := string(crs)
+	if crs == WGS84 {
+		name = "urn:ogc:def:crs:OGC:1.3:CRS84"
+	} else if code := epsgCode(crs); code != 0 {
+		name = fmt.Sprintf("urn:ogc:def:crs:EPSG::%d", code)
+	}
+	return map[string]any{
+		"type":       "name",
+		"properties": map[string]any{"name": name},
+	}
+}
@@ -0,0 +1,138 @@
+package gcoord
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+const bboxFC = `{"type":"FeatureCollection",
+	"crs":{"type":"name","properties":{"name":"urn:ogc:def:crs:OGC:1.3:CRS84"}},
+	"bbox":[116.3,39.8,116.5,40.0],
+	"features":[
+		{"type":"Feature","bbox":[116.3,39.8,10,116.4,39.9,20],"geometry":{"type":"LineString","coordinates":[[116.3,39.8,10],[116.4,39.9,20]]}},
+		{"type":"Feature","geometry":{"type":"Point","coordinates":[116.5,40.0]}}
+	]}`
+
+// wantBBox 按转换后的坐标计算外包矩形
+func wantBBox(t *testing.T, points ...Position) []float64 {
+	t.Helper()
+	var b bounds
+	for _, p := range points {
+		q, err := Transform(Position{p[0], p[1]}, WGS84, EPSG3857)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b.add(q[0], q[1])
+	}
+	return []float64{b.minX, b.minY, b.maxX, b.maxY}
+}
+
+func bboxOf(t *testing.T, obj any) []float64 {
+	t.Helper()
+	raw, ok := obj.(map[string]any)["bbox"].([]any)
+	if !ok {
+		t.Fatalf("missing bbox in %v", obj)
+	}
+	out := make([]float64, len(raw))
+	for i, v := range raw {
+		out[i] = toFloat(v)
+	}
+	return out
+}
+
+func approxSlice(a, b []float64, tol float64) bool {
+	return len(a) == len(b) && approxPos(a, b, tol)
+}
+
+func TestTransformBBoxAndCRS(t *testing.T) {
+	out, err := TransformWithOptions(bboxFC, WGS84, EPSG3857, TransformOptions{AddBBox: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fc map[string]any
+	if err := json.Unmarshal([]byte(out), &fc); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := bboxOf(t, fc), wantBBox(t, Position{116.3, 39.8}, Position{116.5, 40.0}); !approxSlice(got, want, 1e-6) {
+		t.Fatalf("collection bbox: got %v want %v", got, want)
+	}
+	features := fc["features"].([]any)
+	// 六元素 bbox 保持 Z 的范围
+	want := wantBBox(t, Position{116.3, 39.8}, Position{116.4, 39.9})
+	want = []float64{want[0], want[1], 10, want[2], want[3], 20}
+	if got := bboxOf(t, features[0]); !approxSlice(got, want, 1e-6) {
+		t.Fatalf("feature bbox: got %v want %v", got, want)
+	}
+	// AddBBox 补充缺失的 bbox
+	if got, want := bboxOf(t, features[1]), wantBBox(t, Position{116.5, 40.0}, Position{116.5, 40.0}); !approxSlice(got, want, 1e-6) {
+		t.Fatalf("added bbox: got %v want %v", got, want)
+	}
+	if _, ok := features[1].(map[string]any)["geometry"].(map[string]any)["bbox"]; ok {
+		t.Fatal("AddBBox should not add bbox to geometries inside features")
+	}
+
+	name := fc["crs"].(map[string]any)["properties"].(map[string]any)["name"]
+	if name != "urn:ogc:def:crs:EPSG::3857" {
+		t.Fatalf("crs: got %v", name)
+	}
+	if crs, err := ParseCRS(name.(string)); err != nil || crs != EPSG3857 {
+		t.Fatalf("crs name should parse back: %v %v", crs, err)
+	}
+
+	// 源与目标相同时同样添加 bbox
+	same, err := TransformWithOptions(`{"type":"LineString","coordinates":[[116.3,39.8],[116.5,40.0]]}`, WGS84, WGS84, TransformOptions{AddBBox: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var line map[string]any
+	if err := json.Unmarshal([]byte(same), &line); err != nil {
+		t.Fatal(err)
+	}
+	if got := bboxOf(t, line); !approxSlice(got, []float64{116.3, 39.8, 116.5, 40.0}, 1e-12) {
+		t.Fatalf("same CRS bbox: got %v", got)
+	}
+
+	// 不设置 AddBBox 时不添加
+	plain, _ := Transform(`{"type":"Point","coordinates":[116.5,40.0]}`, WGS84, GCJ02)
+	if strings.Contains(plain, "bbox") {
+		t.Fatalf("unexpected bbox: %s", plain)
+	}
+}
+
+func TestStreamBBoxAndCRS(t *testing.T) {
+	var sb strings.Builder
+	if err := TransformStreamWithOptions(strings.NewReader(bboxFC), &sb, WGS84, EPSG3857, TransformOptions{AddBBox: true}); err != nil {
+		t.Fatal(err)
+	}
+	var fc map[string]any
+	if err := json.Unmarshal([]byte(sb.String()), &fc); err != nil {
+		t.Fatalf("invalid output %s: %v", sb.String(), err)
+	}
+	// 顶层 bbox 位于 features 之前，按角点转换；直线经纬度网格在 Web 墨卡托下仍为矩形
+	if got, want := bboxOf(t, fc), wantBBox(t, Position{116.3, 39.8}, Position{116.5, 40.0}); !approxSlice(got, want, 1e-6) {
+		t.Fatalf("stream bbox: got %v want %v", got, want)
+	}
+	if name := fc["crs"].(map[string]any)["properties"].(map[string]any)["name"]; name != "urn:ogc:def:crs:EPSG::3857" {
+		t.Fatalf("crs: got %v", name)
+	}
+	if len(bboxOf(t, fc["features"].([]any)[1])) != 4 {
+		t.Fatal("feature bbox not added")
+	}
+
+	// 没有 bbox 时在末尾补充
+	sb.Reset()
+	in := `{"type":"Feature","geometry":{"type":"Point","coordinates":[116.5,40.0]}}`
+	if err := TransformStreamWithOptions(strings.NewReader(in), &sb, WGS84, GCJ02, TransformOptions{AddBBox: true}); err != nil {
+		t.Fatal(err)
+	}
+	var f map[string]any
+	if err := json.Unmarshal([]byte(sb.String()), &f); err != nil {
+		t.Fatalf("invalid output %s: %v", sb.String(), err)
+	}
+	p, _ := Transform(Position{116.5, 40.0}, WGS84, GCJ02)
+	if got := bboxOf(t, f); !approxSlice(got, []float64{p[0], p[1], p[0], p[1]}, 1e-9) {
+		t.Fatalf("stream feature bbox: %v", got)
+	}
+}
@@ -1,29 +1,77 @@
 package gcoord
 
-import "math"
+import (
+	"fmt"
+	"math"
+)
 
 // 使用 constants.go 中定义的常量
 
-// BD09ToGCJ02 百度经纬度转火星坐标
+// BD09ToGCJ02 百度经纬度转火星坐标，使用近似反算公式，往返误差可达约 20 厘米。
+// 需要精确反解时使用 TransformOptions.ExactBD09
 func BD09ToGCJ02(coord Position) Position {
-	lon, lat := coord[0], coord[1]
+	lon, lat := bd09ToGCJ02(coord[0], coord[1])
+	return withXY(coord, lon, lat)
+}
+
+// GCJ02ToBD09 火星坐标转百度经纬度
+func GCJ02ToBD09(coord Position) Position {
+	lon, lat := gcj02ToBD09(coord[0], coord[1])
+	return withXY(coord, lon, lat)
+}
+
+func bd09ToGCJ02(lon, lat float64) (float64, float64) {
 	x := lon - 0.0065
 	y := lat - 0.006
 	z := math.Sqrt(x*x+y*y) - 0.00002*math.Sin(y*BaiduFactor)
 	theta := math.Atan2(y, x) - 0.000003*math.Cos(x*BaiduFactor)
 	newLon := z * math.Cos(theta)
 	newLat := z * math.Sin(theta)
-	return Position{newLon, newLat}
+	return newLon, newLat
 }
 
-// GCJ02ToBD09 火星坐标转百度经纬度
-func GCJ02ToBD09(coord Position) Position {
-	lon, lat := coord[0], coord[1]
+func gcj02ToBD09(lon, lat float64) (float64, float64) {
 	x := lon
 	y := lat
 	z := math.Sqrt(x*x+y*y) + 0.00002*math.Sin(y*BaiduFactor)
 	theta := math.Atan2(y, x) + 0.000003*math.Cos(x*BaiduFactor)
 	newLon := z*math.Cos(theta) + 0.0065
 	newLat := z*math.Sin(theta) + 0.006
-	return Position{newLon, newLat}
+	return newLon, newLat
+}
+
+// bd09ToGCJ02Step BD09 转 GCJ02，o.ExactBD09 为 true 时以近似公式为初值，
+// 迭代反解 gcj02ToBD09 直到残差不超过精度要求
+func bd09ToGCJ02Step(lon, lat float64, o *TransformOptions) (float64, float64, error) {
+	x, y := bd09ToGCJ02(lon, lat)
+	if !o.ExactBD09 || math.IsNaN(x) || math.IsNaN(y) || math.IsInf(x, 0) || math.IsInf(y, 0) {
+		return x, y, nil
+	}
+
+	tol := o.InversePrecision
+	if tol <= 0 {
+		tol = ExactInversePrecision
+	}
+	maxIter := o.maxIterations()
+	var rx, ry float64
+	for i := 0; i < maxIter; i++ {
+		bx, by := gcj02ToBD09(x, y)
+		rx, ry = bx-lon, by-lat
+		if math.Abs(rx) <= tol && math.Abs(ry) <= tol {
+			return x, y, nil
+		}
+		// gcj02ToBD09 接近平移，雅可比矩阵近似为单位矩阵
+		x -= rx
+		y -= ry
+	}
+	return x, y, &TransformError{
+		Type:    ErrTransformFailed,
+		Message: fmt.Sprintf("BD09 精确反解在 %d 次迭代内未收敛: [%v, %v]", maxIter, lon, lat),
+		Details: map[string]interface{}{
+			"position":   Position{lon, lat},
+			"iterations": maxIter,
+			"residual":   math.Max(math.Abs(rx), math.Abs(ry)),
+			"precision":  tol,
+		},
+	}
 }
@@ -23,7 +23,7 @@ var ll2mc = [][]float64{
 	{-0.0003218135878613132, 111320.7020701615, 0.00369383431289, 823725.6402795718, 0.46104986909093, 2351.343141331292, 1.58060784298199, 8.77738589078284, 0.37238884252424, 7.45},
 }
 
-func transformBD(x, y float64, factors []float64) Position {
+func transformBD(x, y float64, factors []float64) (float64, float64) {
 	cc := math.Abs(y) / factors[9]
 	xt := factors[0] + factors[1]*math.Abs(x)
 	yt := factors[2] + factors[3]*cc + factors[4]*cc*cc + factors[5]*cc*cc*cc + factors[6]*math.Pow(cc, 4) + factors[7]*math.Pow(cc, 5) + factors[8]*math.Pow(cc, 6)
@@ -33,12 +33,22 @@ func transformBD(x, y float64, factors []float64) Position {
 	if y < 0 {
 		yt = -yt
 	}
-	return Position{xt, yt}
+	return xt, yt
 }
 
 // BD09toBD09MC 百度经纬度->百度墨卡托
 func BD09toBD09MC(coord Position) Position {
-	lng, lat := coord[0], coord[1]
+	x, y := bd09ToBD09MC(coord[0], coord[1])
+	return withXY(coord, x, y)
+}
+
+// BD09MCtoBD09 百度墨卡托->百度经纬度
+func BD09MCtoBD09(coord Position) Position {
+	lng, lat := bd09MCToBD09(coord[0], coord[1])
+	return withXY(coord, lng, lat)
+}
+
+func bd09ToBD09MC(lng, lat float64) (float64, float64) {
 	var f []float64
 	for i := 0; i < len(llbands); i++ {
 		if math.Abs(lat) > llbands[i] {
@@ -52,9 +62,7 @@ func BD09toBD09MC(coord Position) Position {
 	return transformBD(lng, lat, f)
 }
 
-// BD09MCtoBD09 百度墨卡托->百度经纬度
-func BD09MCtoBD09(coord Position) Position {
-	x, y := coord[0], coord[1]
+func bd09MCToBD09(x, y float64) (float64, float64) {
 	var f []float64
 	for i := 0; i < len(mcbands); i++ {
 		if math.Abs(y) >= mcbands[i] {
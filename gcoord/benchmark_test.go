@@ -11,6 +11,13 @@ func BenchmarkTransform_WGS84_GCJ02(b *testing.B) {
 	}
 }
 
+func BenchmarkTransform_GCJ02_WGS84(b *testing.B) {
+	p := Position{116.403, 39.909}
+	for i := 0; i < b.N; i++ {
+		_, _ = Transform(p, GCJ02, WGS84)
+	}
+}
+
 func BenchmarkTransform_GCJ02_BD09(b *testing.B) {
 	p := Position{116.404, 39.915}
 	for i := 0; i < b.N; i++ {
@@ -24,3 +31,49 @@ func BenchmarkTransform_WGS84_EPSG3857(b *testing.B) {
 		_, _ = Transform(p, WGS84, EPSG3857)
 	}
 }
+
+// 批量转换基准：对比逐点 Transform 与原地批量转换的分配情况
+const benchBatchSize = 10000
+
+func benchFlatCoords() []float64 {
+	coords := make([]float64, 0, benchBatchSize*2)
+	for i := 0; i < benchBatchSize; i++ {
+		coords = append(coords, 116.0+float64(i%1000)*0.001, 39.0+float64(i/1000)*0.01)
+	}
+	return coords
+}
+
+func BenchmarkBatch_PerPointTransform_WGS84_BD09(b *testing.B) {
+	coords := benchFlatCoords()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for j := 0; j < len(coords); j += 2 {
+			_, _ = Transform(Position{coords[j], coords[j+1]}, WGS84, BD09)
+		}
+	}
+}
+
+func BenchmarkBatch_TransformFlat_WGS84_BD09(b *testing.B) {
+	src := benchFlatCoords()
+	coords := make([]float64, len(src))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		copy(coords, src)
+		_ = TransformFlat(coords, 2, WGS84, BD09)
+	}
+}
+
+func BenchmarkBatch_TransformPoints_WGS84_BD09(b *testing.B) {
+	src := benchFlatCoords()
+	points := make([][2]float64, len(src)/2)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for j := range points {
+			points[j] = [2]float64{src[2*j], src[2*j+1]}
+		}
+		_ = TransformPoints(points, WGS84, BD09)
+	}
+}
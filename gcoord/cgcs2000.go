@@ -0,0 +1,110 @@
+package gcoord
+
+import (
+	"fmt"
+	"math"
+)
+
+// CGCS2000 高斯-克吕格投影带的带号范围，覆盖中央经线 75°E–135°E
+const (
+	gk6MinZone, gk6MaxZone = 13, 23
+	gk3MinZone, gk3MaxZone = 25, 45
+)
+
+// CGCS2000GK 返回 CGCS2000 高斯-克吕格投影坐标系。
+//
+// zoneWidth 为 3 或 6（度）。6 度带带号为 13–23，中央经线为 6·zone-3；
+// 3 度带带号为 25–45，中央经线为 3·zone。zonePrefix 为 true 时东坐标含带号，
+// 即伪东偏移为 zone·1000000+500000（如 EPSG:4527），否则为 500000（如 EPSG:4548）。
+// 带号超出范围时返回的坐标系未注册，转换时返回 ErrUnsupportedCRS 错误。
+//
+// 示例：
+//
+//	xy, err := Transform(Position{116.397, 39.908}, WGS84, CGCS2000GK(3, 39, true))
+func CGCS2000GK(zoneWidth, zone int, zonePrefix bool) CRSTypes {
+	kind := "GK"
+	if zoneWidth != 6 {
+		kind = fmt.Sprintf("%dGK", zoneWidth)
+	}
+	if zonePrefix {
+		return CRSTypes(fmt.Sprintf("CGCS2000_%s_ZONE_%d", kind, zone))
+	}
+	return CRSTypes(fmt.Sprintf("CGCS2000_%s_CM_%dE", kind, gkCentralMeridian(zoneWidth, zone)))
+}
+
+// GaussKrugerZone 返回经度所在的高斯-克吕格投影带带号，zoneWidth 为 3 或 6（度）。
+// 6 度带从 0° 起每 6° 一带，3 度带从 1.5° 起每 3° 一带
+func GaussKrugerZone(lon float64, zoneWidth int) int {
+	if zoneWidth == 3 {
+		return int(math.Floor((lon + 1.5) / 3))
+	}
+	return int(math.Floor(lon/6)) + 1
+}
+
+// gkCentralMeridian 返回投影带的中央经线（度）
+func gkCentralMeridian(zoneWidth, zone int) int {
+	if zoneWidth == 3 {
+		return 3 * zone
+	}
+	return 6*zone - 3
+}
+
+// registerCGCS2000GK 注册 CGCS2000 高斯-克吕格投影带。
+//
+// CGCS2000 与 WGS84 的差异在厘米级，远小于本库的转换精度，两者之间按恒等转换处理，见 registerDatums
+func registerCGCS2000GK() {
+	// EPSG:4491–4501 6 度带含带号，4502–4512 6 度带不含带号，
+	// 4513–4533 3 度带含带号，4534–4554 3 度带不含带号
+	for _, g := range []struct {
+		width, minZone, maxZone int
+		prefix                  bool
+		code                    int
+	}{
+		{6, gk6MinZone, gk6MaxZone, true, 4491},
+		{6, gk6MinZone, gk6MaxZone, false, 4502},
+		{3, gk3MinZone, gk3MaxZone, true, 4513},
+		{3, gk3MinZone, gk3MaxZone, false, 4534},
+	} {
+		for zone := g.minZone; zone <= g.maxZone; zone++ {
+			registerGK(g.width, zone, g.prefix, g.code+zone-g.minZone)
+		}
+	}
+}
+
+// registerGK 注册一个 CGCS2000 高斯-克吕格投影带
+func registerGK(width, zone int, prefix bool, code int) {
+	cm := gkCentralMeridian(width, zone)
+	fe := 500000.0
+	if prefix {
+		fe += float64(zone) * 1e6
+	}
+
+	epsgName := "CGCS2000 / Gauss-Kruger"
+	if width == 3 {
+		epsgName = "CGCS2000 / 3-degree Gauss-Kruger"
+	}
+	desc := fmt.Sprintf("CGCS2000 高斯-克吕格投影 %d 度带第 %d 带，中央经线 %d°E", width, zone, cm)
+	if prefix {
+		epsgName += fmt.Sprintf(" zone %d", zone)
+		desc += "，东坐标含带号"
+	} else {
+		epsgName += fmt.Sprintf(" CM %dE", cm)
+	}
+
+	name := CGCS2000GK(width, zone, prefix)
+	mustRegisterCRS(CRSInfo{
+		Name:        name,
+		Description: desc,
+		Aliases:     []string{fmt.Sprintf("EPSG:%d", code), epsgName},
+		Projected:   true,
+		Extent:      [4]float64{fe - 1e6, 0, fe + 1e6, tmMaxNorthing},
+	})
+
+	tm := newTransverseMercator(CGCS2000Ellipsoid, TransverseMercator{
+		CentralMeridian: float64(cm),
+		FalseEasting:    fe,
+	})
+	forward, inverse := tm.steps()
+	registerSteps(CGCS2000, map[CRSTypes]pointStep{name: forward})
+	registerSteps(name, map[CRSTypes]pointStep{CGCS2000: inverse})
+}
@@ -0,0 +1,98 @@
+package gcoord
+
+import "testing"
+
+func TestTransverseMercator(t *testing.T) {
+	// EPSG Guidance Note 7-2 横轴墨卡托算例（OSGB 1936 / British National Grid）
+	tm := newTransverseMercator(Ellipsoid{A: 6377563.396, F: 1 / 299.3249646}, TransverseMercator{
+		CentralMeridian:  -2,
+		LatitudeOfOrigin: 49,
+		ScaleFactor:      0.9996012717,
+		FalseEasting:     400000,
+		FalseNorthing:    -100000,
+	})
+	x, y := tm.forward(0.5, 50.5)
+	if !approx(x, 577274.98, 0.01) || !approx(y, 69740.49, 0.01) {
+		t.Fatalf("forward = %.3f, %.3f, want 577274.98, 69740.49", x, y)
+	}
+	lon, lat := tm.inverse(x, y)
+	if !approx(lon, 0.5, 1e-10) || !approx(lat, 50.5, 1e-10) {
+		t.Fatalf("inverse = %v, %v", lon, lat)
+	}
+
+	// 中央经线上到极点的北向坐标为子午线弧长
+	w := newTransverseMercator(WGS84Ellipsoid, TransverseMercator{})
+	if _, y := w.forward(0, 90); !approx(y, 10001965.7293, 1e-3) {
+		t.Fatalf("quarter meridian = %.4f", y)
+	}
+	for lon := -9.0; lon <= 9; lon += 1.5 {
+		for lat := -80.0; lat <= 80; lat += 10 {
+			x, y := w.forward(lon, lat)
+			if l, b := w.inverse(x, y); !approx(l, lon, 1e-11) || !approx(b, lat, 1e-11) {
+				t.Fatalf("roundtrip (%v, %v) -> %v, %v", lon, lat, l, b)
+			}
+		}
+	}
+}
+
+func TestCGCS2000GK(t *testing.T) {
+	p := Position{116.397, 39.908}
+
+	zone := GaussKrugerZone(p[0], 3)
+	if zone != 39 || GaussKrugerZone(p[0], 6) != 20 {
+		t.Fatalf("GaussKrugerZone = %d, %d, want 39, 20", zone, GaussKrugerZone(p[0], 6))
+	}
+
+	prefixed, err := Transform(p, CGCS2000, CGCS2000GK(3, zone, true))
+	if err != nil {
+		t.Fatalf("transform: %v", err)
+	}
+	plain, err := Transform(p, CGCS2000, CGCS2000GK(3, zone, false))
+	if err != nil {
+		t.Fatalf("transform: %v", err)
+	}
+	if !approx(prefixed[0]-plain[0], 39e6, 1e-6) || prefixed[1] != plain[1] {
+		t.Fatalf("prefix mismatch: %v vs %v", prefixed, plain)
+	}
+	// 中央经线 117°E 以西约 51 千米，北向坐标约 4420 千米
+	if !approx(plain[0], 500000-51.4e3, 1e3) || !approx(plain[1], 4420e3, 5e3) {
+		t.Fatalf("unexpected GK coordinate %v", plain)
+	}
+
+	// 中央经线上的点东坐标为伪东偏移
+	if cm, _ := Transform(Position{117, 30}, CGCS2000, CGCS2000GK(6, 20, true)); !approx(cm[0], 20500000, 1e-6) {
+		t.Fatalf("central meridian easting = %v", cm[0])
+	}
+
+	// EPSG 代码与 GCJ02 往返
+	for code, crs := range map[string]CRSTypes{
+		"EPSG:4527": CGCS2000GK(3, 39, true),
+		"EPSG:4548": CGCS2000GK(3, 39, false),
+		"EPSG:4498": CGCS2000GK(6, 20, true),
+		"EPSG:4509": CGCS2000GK(6, 20, false),
+	} {
+		parsed, err := ParseCRS(code)
+		if err != nil || parsed != crs {
+			t.Fatalf("ParseCRS(%s) = %v, %v, want %v", code, parsed, err, crs)
+		}
+		gcj, _ := Transform(p, WGS84, GCJ02)
+		xy, err := Transform(gcj, GCJ02, crs)
+		if err != nil {
+			t.Fatalf("GCJ02 -> %s: %v", crs, err)
+		}
+		back, err := Transform(xy, crs, GCJ02)
+		if err != nil || !approxPos(back, gcj, TestPrecisionRoundtrip) {
+			t.Fatalf("%s roundtrip: %v, %v", crs, back, err)
+		}
+	}
+
+	if _, err := Transform(p, WGS84, CGCS2000GK(3, 12, true)); err == nil {
+		t.Fatalf("expected error for zone out of range")
+	}
+
+	// 严格模式下不含带号的东坐标不属于含带号的投影带
+	_, err = TransformWithOptions(plain, CGCS2000GK(3, zone, true), WGS84, TransformOptions{Strict: true})
+	if err == nil {
+		t.Fatalf("expected strict error for easting without zone prefix")
+	}
+}
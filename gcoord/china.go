@@ -0,0 +1,204 @@
+package gcoord
+
+import "fmt"
+
+// OffsetRegion 可选的 GCJ02 加偏区域，不同地图服务商对港澳台是否加偏的处理不同
+type OffsetRegion uint8
+
+const (
+	RegionHongKong OffsetRegion = 1 << iota
+	RegionMacau
+	RegionTaiwan
+)
+
+// 简化边界，顶点为 [经度, 纬度]，陆地边界与真实边界的偏差约 10~20 公里，
+// 海岸线向外延伸到近海，覆盖沿海岛屿。
+// 大陆与海南的多边形覆盖港澳及金门、马祖附近海域，判断时先检查港澳台多边形
+
+var chinaMainland = newRegion([][2]float64{
+	// 中朝边界（鸭绿江、图们江）
+	{124.37, 40.05}, {125.0, 40.5}, {126.0, 41.0}, {126.9, 41.7}, {128.0, 41.9},
+	{128.9, 42.0}, {129.4, 42.4}, {129.9, 42.95}, {130.3, 42.7}, {130.7, 42.3},
+	// 中俄东段边界（乌苏里江、黑龙江、额尔古纳河）
+	{131.0, 42.9}, {131.3, 43.5}, {131.2, 44.9}, {132.0, 45.3}, {133.1, 45.1},
+	{133.5, 45.9}, {134.0, 46.6}, {134.2, 47.4}, {134.7, 48.45}, {134.0, 48.4}, {133.5, 48.15},
+	{132.6, 47.7}, {130.9, 47.9}, {130.6, 48.9}, {129.5, 49.4}, {127.9, 49.6},
+	{127.6, 50.35}, {126.6, 51.75}, {126.0, 52.8}, {125.0, 53.2}, {123.5, 53.5},
+	{122.4, 53.5}, {121.2, 53.3}, {120.0, 52.6}, {120.6, 52.0}, {119.3, 50.4},
+	{117.9, 49.7},
+	// 中蒙边界
+	{116.7, 49.85}, {115.5, 48.2}, {115.6, 47.9}, {117.4, 47.6}, {118.5, 47.9},
+	{119.7, 47.2}, {119.9, 46.7}, {118.2, 46.7}, {117.4, 46.3}, {116.0, 45.7},
+	{114.5, 45.4}, {113.6, 44.8}, {111.9, 45.1}, {111.4, 44.4}, {111.9, 43.7},
+	{110.4, 42.8}, {107.5, 42.4}, {105.0, 41.6}, {101.8, 42.5}, {100.0, 42.6},
+	{97.2, 42.8}, {96.4, 42.8}, {95.4, 44.3}, {93.5, 45.0}, {90.9, 45.3},
+	{90.7, 46.0}, {91.0, 46.6}, {90.4, 47.4}, {90.0, 47.9}, {88.9, 48.1},
+	// 中俄西段、中哈、中吉、中塔边界
+	{87.8, 49.2}, {87.3, 49.1}, {86.8, 48.6}, {85.7, 48.4}, {85.5, 47.1},
+	{83.0, 47.2}, {82.3, 45.5}, {82.4, 45.3}, {80.8, 45.1}, {80.2, 44.2},
+	{80.2, 42.9}, {80.2, 42.2}, {79.0, 41.8}, {77.9, 41.0}, {76.8, 41.0},
+	{75.6, 40.6}, {74.9, 40.5}, {73.8, 39.8}, {73.5, 39.4}, {73.7, 38.6},
+	// 中巴、中印、中尼、中不边界（按实际控制线）
+	{74.9, 37.2}, {76.0, 36.7}, {77.8, 35.5}, {78.1, 35.5}, {78.7, 34.5},
+	{79.0, 33.9}, {78.7, 33.1}, {79.5, 32.6}, {78.8, 31.5}, {79.3, 31.0},
+	{80.0, 30.8}, {80.9, 30.3}, {81.3, 30.2}, {82.1, 30.3}, {83.3, 29.5},
+	{84.1, 29.3}, {85.1, 28.5}, {85.35, 28.25}, {86.0, 28.0}, {86.9, 28.0}, {88.1, 27.9},
+	{88.8, 28.1}, {88.9, 27.3}, {89.2, 27.3}, {89.6, 28.2}, {90.5, 28.1},
+	{91.6, 27.9}, {92.1, 27.8}, {93.2, 27.9}, {94.3, 28.6}, {95.3, 28.9},
+	{96.2, 29.1}, {97.0, 28.3},
+	// 中缅、中老、中越边界
+	{97.6, 28.5}, {98.6, 27.4}, {98.7, 26.2}, {97.7, 25.0}, {97.5, 24.4},
+	{97.8, 23.9}, {98.9, 23.2}, {99.5, 22.9}, {99.2, 22.1}, {100.1, 21.5},
+	{101.1, 21.6}, {101.7, 21.2}, {102.1, 22.4}, {102.5, 22.7}, {103.5, 22.7},
+	{103.85, 22.45}, {104.0, 22.45}, {104.8, 22.8}, {105.3, 23.3}, {106.7, 22.9}, {106.7, 22.0},
+	{107.4, 21.6}, {107.95, 21.5},
+	// 海岸线，北部湾、雷州半岛、珠江口
+	{108.1, 21.2}, {108.7, 20.8}, {109.3, 20.8}, {109.6, 20.2}, {110.7, 20.2},
+	{110.9, 20.8}, {111.0, 21.2}, {111.7, 21.4}, {112.9, 21.5}, {113.5, 21.8},
+	{114.3, 22.0}, {114.6, 22.2},
+	// 粤东、福建（南澳、平潭）、浙江（舟山、嵊泗）
+	{115.3, 22.5}, {116.0, 22.6}, {116.6, 22.8}, {117.2, 23.2}, {117.6, 23.5},
+	{118.3, 24.2}, {118.8, 24.5}, {119.4, 24.9}, {120.0, 25.4}, {120.2, 26.0},
+	{120.5, 26.5}, {120.9, 26.9}, {121.4, 27.6}, {121.9, 28.2}, {122.3, 28.9},
+	{122.6, 29.5}, {122.9, 30.2}, {123.0, 30.8},
+	// 长江口、江苏、山东半岛
+	{122.6, 31.3}, {122.2, 31.8}, {121.6, 32.4}, {121.2, 33.0}, {120.9, 33.8},
+	{120.4, 34.6}, {119.9, 35.0}, {120.3, 35.4}, {120.8, 35.9}, {121.5, 36.4},
+	{122.6, 36.7}, {122.9, 37.1}, {122.8, 37.55}, {122.0, 37.75},
+	// 庙岛群岛（长岛），渤海海峡北侧留出通道，使渤海中部位于区域外
+	{121.2, 38.0}, {121.1, 38.5}, {120.5, 38.5}, {120.4, 38.0}, {120.1, 37.7},
+	// 渤海沿岸
+	{119.0, 37.3}, {118.9, 38.0}, {117.7, 38.5}, {117.6, 39.1}, {118.6, 39.0},
+	{119.5, 39.7}, {120.9, 40.4}, {121.9, 40.8}, {122.1, 40.4}, {121.3, 39.5},
+	// 辽东半岛（旅顺口、长海）
+	{120.95, 38.85}, {121.05, 38.6}, {121.7, 38.7}, {122.9, 38.9}, {123.5, 39.4},
+	{124.1, 39.65},
+})
+
+var chinaHainan = newRegion([][2]float64{
+	{108.5, 19.1}, {109.1, 18.2}, {109.6, 18.0}, {110.3, 18.3}, {110.7, 18.7},
+	{111.2, 19.6}, {111.0, 20.2}, {110.5, 20.3}, {109.5, 20.3}, {108.5, 19.8},
+})
+
+// 西沙群岛（三沙市永兴岛）
+var chinaXisha = newRegion([][2]float64{
+	{111.1, 15.7}, {112.9, 15.7}, {112.9, 17.2}, {111.1, 17.2},
+})
+
+var chinaRegions = []*region{chinaMainland, chinaHainan, chinaXisha}
+
+var hongKongRegion = newRegion([][2]float64{
+	{113.83, 22.19}, {114.0, 22.13}, {114.45, 22.13}, {114.45, 22.5}, {114.23, 22.56},
+	{114.07, 22.5}, {113.9, 22.49}, {113.83, 22.35},
+})
+
+var macauRegion = newRegion([][2]float64{
+	{113.528, 22.18}, {113.555, 22.22}, {113.565, 22.2}, {113.6, 22.16}, {113.6, 22.11},
+	{113.545, 22.11}, {113.545, 22.17},
+})
+
+// 台湾本岛及澎湖、绿岛、兰屿，金门，马祖（南竿、北竿、莒光、东引）
+var taiwanRegions = []*region{
+	newRegion([][2]float64{
+		{119.3, 23.0}, {120.0, 22.5}, {120.6, 21.75}, {121.0, 21.75}, {121.7, 21.9},
+		{121.7, 22.8}, {121.9, 24.0}, {122.1, 24.6}, {122.1, 25.2}, {121.6, 25.45},
+		{121.0, 25.2}, {120.0, 24.0}, {119.3, 23.9},
+	}),
+	newRegion([][2]float64{
+		{118.215, 24.37}, {118.5, 24.37}, {118.5, 24.56}, {118.215, 24.56},
+	}),
+	newRegion([][2]float64{
+		{119.89, 25.93}, {120.02, 25.93}, {120.02, 26.30}, {119.89, 26.30},
+	}),
+	newRegion([][2]float64{
+		{120.45, 26.33}, {120.54, 26.33}, {120.54, 26.40}, {120.45, 26.40},
+	}),
+}
+
+// region 带外包矩形的多边形
+type region struct {
+	ring                           [][2]float64
+	minLon, maxLon, minLat, maxLat float64
+}
+
+func newRegion(ring [][2]float64) *region {
+	r := &region{ring: ring, minLon: ring[0][0], maxLon: ring[0][0], minLat: ring[0][1], maxLat: ring[0][1]}
+	for _, p := range ring[1:] {
+		r.minLon = min(r.minLon, p[0])
+		r.maxLon = max(r.maxLon, p[0])
+		r.minLat = min(r.minLat, p[1])
+		r.maxLat = max(r.maxLat, p[1])
+	}
+	return r
+}
+
+// contains 先用外包矩形过滤，再用射线法判断点是否在多边形内
+func (r *region) contains(lon, lat float64) bool {
+	if lon < r.minLon || lon > r.maxLon || lat < r.minLat || lat > r.maxLat {
+		return false
+	}
+	in := false
+	n := len(r.ring)
+	for i, j := 0, n-1; i < n; j, i = i, i+1 {
+		xi, yi := r.ring[i][0], r.ring[i][1]
+		xj, yj := r.ring[j][0], r.ring[j][1]
+		if (yi > lat) != (yj > lat) && lon < (xj-xi)*(lat-yi)/(yj-yi)+xi {
+			in = !in
+		}
+	}
+	return in
+}
+
+// chinaRegion 返回点所在的区域，大陆、海南与南海诸岛为 0，不在中国范围内时 ok 为 false。
+// NaN 无法通过外包矩形过滤，返回 false
+func chinaRegion(lon, lat float64) (r OffsetRegion, ok bool) {
+	if !isInChinaBbox(lon, lat) {
+		return 0, false
+	}
+	switch {
+	case hongKongRegion.contains(lon, lat):
+		return RegionHongKong, true
+	case macauRegion.contains(lon, lat):
+		return RegionMacau, true
+	case anyContains(taiwanRegions, lon, lat):
+		return RegionTaiwan, true
+	}
+	return 0, anyContains(chinaRegions, lon, lat)
+}
+
+// anyContains 判断点是否位于任一多边形内
+func anyContains(regions []*region, lon, lat float64) bool {
+	for _, r := range regions {
+		if r.contains(lon, lat) {
+			return true
+		}
+	}
+	return false
+}
+
+// isInChina 判断点是否位于 GCJ02 加偏区域，exclude 中的港澳台区域不加偏
+func isInChina(lon, lat float64, exclude OffsetRegion) bool {
+	r, ok := chinaRegion(lon, lat)
+	return ok && r&exclude == 0
+}
+
+// offsetApplies 判断 WGS84↔GCJ02 是否需要对点加偏，
+// o.OutOfChina 为 OutOfChinaError 时境外坐标返回错误
+func offsetApplies(lon, lat float64, o *TransformOptions) (bool, error) {
+	r, ok := chinaRegion(lon, lat)
+	if !ok && o.OutOfChina == OutOfChinaError {
+		return false, &TransformError{
+			Type:    ErrInvalidInput,
+			Message: fmt.Sprintf("坐标不在中国范围内: [%v, %v]", lon, lat),
+			Details: map[string]interface{}{
+				"position": Position{lon, lat},
+			},
+		}
+	}
+	return ok && r&o.ExcludeRegions == 0, nil
+}
+
+// IsInChina 判断 WGS84 或 GCJ02 坐标是否位于加偏区域（含港澳台），使用简化边界
+func IsInChina(coord Position) bool {
+	return len(coord) >= 2 && isInChina(coord[0], coord[1], 0)
+}
@@ -0,0 +1,96 @@
+package gcoord
+
+import "testing"
+
+func TestIsInChina(t *testing.T) {
+	inside := map[string]Position{
+		"北京": {116.40, 39.90}, "上海": {121.47, 31.23}, "乌鲁木齐": {87.60, 43.80},
+		"哈尔滨": {126.63, 45.75}, "拉萨": {91.13, 29.65}, "喀什": {75.99, 39.47},
+		"阿里": {80.10, 32.50}, "日喀则": {88.88, 29.27}, "林芝": {94.36, 29.65},
+		"昆明": {102.71, 25.04}, "景洪": {100.80, 22.00}, "南宁": {108.37, 22.82},
+		"漠河": {122.54, 52.97}, "抚远": {134.29, 48.36}, "珲春": {130.37, 42.87},
+		"延吉": {129.50, 42.90}, "阿勒泰": {88.13, 47.84}, "伊宁": {81.32, 43.92},
+		"二连浩特": {111.98, 43.65}, "满洲里": {117.43, 49.60}, "海拉尔": {119.76, 49.21},
+		"厦门": {118.09, 24.48}, "青岛": {120.38, 36.07}, "大连": {121.61, 38.91},
+		"威海": {122.12, 37.51}, "舟山": {122.20, 30.00}, "湛江": {110.36, 21.27},
+		"深圳": {114.06, 22.54}, "珠海": {113.57, 22.27}, "海口": {110.32, 20.03},
+		"三亚": {109.51, 18.25},
+		// 边境口岸与沿海、海岛
+		"北海": {109.12, 21.48}, "防城港": {108.35, 21.60}, "旅顺口": {121.26, 38.81},
+		"黑河": {127.50, 50.25}, "河口": {103.95, 22.50}, "霍尔果斯": {80.40, 44.20},
+		"吉隆": {85.30, 28.40}, "平潭": {119.79, 25.50}, "南澳": {117.02, 23.42},
+		"长岛": {120.74, 37.92}, "嵊泗": {122.45, 30.72}, "三沙": {112.34, 16.83},
+		"涠洲岛": {109.10, 21.03}, "金门": {118.32, 24.45}, "马祖": {119.95, 26.16},
+		"兰屿": {121.55, 22.05}, "澎湖": {119.58, 23.57},
+	}
+	outside := map[string]Position{
+		"乌兰巴托": {106.90, 47.90}, "乔巴山": {114.50, 48.07}, "首尔": {126.98, 37.57},
+		"平壤": {125.75, 39.03}, "东京": {139.70, 35.70}, "福冈": {130.40, 33.60},
+		"济州": {126.50, 33.40}, "符拉迪沃斯托克": {131.90, 43.10}, "哈巴罗夫斯克": {135.07, 48.48},
+		"赤塔": {113.50, 52.00}, "阿拉木图": {76.90, 43.25}, "比什凯克": {74.60, 42.87},
+		"伊斯兰堡": {73.05, 33.70}, "列城": {77.58, 34.16}, "加德满都": {85.30, 27.70},
+		"廷布": {89.64, 27.47}, "达卡": {90.40, 23.80}, "曼德勒": {96.10, 21.97},
+		"河内": {105.85, 21.03}, "万象": {102.60, 17.97}, "黄海": {123.50, 35.00},
+		"东海": {124.50, 29.00}, "渤海": {120.50, 39.00}, "南海": {114.00, 15.00},
+	}
+	for name, p := range inside {
+		if !IsInChina(p) {
+			t.Errorf("%s %v should be inside", name, p)
+		}
+	}
+	for name, p := range outside {
+		if IsInChina(p) {
+			t.Errorf("%s %v should be outside", name, p)
+		}
+		if got := WGS84ToGCJ02(p); got[0] != p[0] || got[1] != p[1] {
+			t.Errorf("%s %v should not be shifted, got %v", name, p, got)
+		}
+	}
+}
+
+func TestExcludeRegions(t *testing.T) {
+	cases := []struct {
+		name   string
+		p      Position
+		region OffsetRegion
+	}{
+		{"香港", Position{114.17, 22.30}, RegionHongKong},
+		{"澳门", Position{113.55, 22.19}, RegionMacau},
+		{"台北", Position{121.56, 25.04}, RegionTaiwan},
+		{"高雄", Position{120.30, 22.62}, RegionTaiwan},
+		{"金门", Position{118.32, 24.45}, RegionTaiwan},
+		{"兰屿", Position{121.55, 22.05}, RegionTaiwan},
+	}
+	for _, c := range cases {
+		shifted, err := TransformWithOptions(c.p, WGS84, GCJ02, TransformOptions{})
+		if err != nil || approxPos(shifted, c.p, 1e-9) {
+			t.Fatalf("%s should be shifted by default: %v (%v)", c.name, shifted, err)
+		}
+		opts := TransformOptions{ExcludeRegions: c.region}
+		for _, pair := range [][2]CRSTypes{{WGS84, GCJ02}, {GCJ02, WGS84}} {
+			got, err := TransformWithOptions(c.p, pair[0], pair[1], opts)
+			if err != nil || !approxPos(got, c.p, 1e-12) {
+				t.Fatalf("%s %s→%s should be unchanged when excluded: %v (%v)", c.name, pair[0], pair[1], got, err)
+			}
+		}
+		// 排除其他区域不影响
+		other := TransformOptions{ExcludeRegions: (RegionHongKong | RegionMacau | RegionTaiwan) &^ c.region}
+		if got, _ := TransformWithOptions(c.p, WGS84, GCJ02, other); !approxPos(got, shifted, 1e-12) {
+			t.Fatalf("%s affected by excluding other regions: %v", c.name, got)
+		}
+	}
+
+	// 深圳不受排除香港影响，厦门不受排除台湾（金门）影响
+	for name, c := range map[string]struct {
+		p      Position
+		region OffsetRegion
+	}{
+		"深圳": {Position{114.06, 22.54}, RegionHongKong},
+		"厦门": {Position{118.09, 24.48}, RegionTaiwan},
+	} {
+		got, _ := TransformWithOptions(c.p, WGS84, GCJ02, TransformOptions{ExcludeRegions: c.region})
+		if approxPos(got, c.p, 1e-9) {
+			t.Fatalf("%s should still be shifted: %v", name, got)
+		}
+	}
+}
@@ -10,9 +10,15 @@ const (
 	// 投影坐标转换精度
 	ProjectionPrecision = 1.0 // 1米
 
-	// 迭代收敛精度
+	// 迭代收敛精度，TransformOptions.InversePrecision 的默认值
 	IterationPrecision = 1e-6
 
+	// 精确反解（如 TransformOptions.ExactBD09）的默认收敛精度，约 0.01 毫米
+	ExactInversePrecision = 1e-10
+
+	// 迭代反解的最大迭代次数，TransformOptions.MaxIterations 的默认值
+	DefaultMaxIterations = 30
+
 	// 中国边界框
 	ChinaMinLon = 72.004
 	ChinaMaxLon = 137.8347
@@ -26,6 +32,10 @@ const (
 	WGS84A = 6378137.0
 	WGS84F = 1.0 / 298.257223563
 
+	// CGCS2000椭球参数
+	CGCS2000A = 6378137.0
+	CGCS2000F = 1.0 / 298.257222101
+
 	// GCJ02椭球参数
 	GCJ02A  = 6378245.0
 	GCJ02E2 = 0.006693421622965823
@@ -1,5 +1,10 @@
 package gcoord
 
+import (
+	"strconv"
+	"strings"
+)
+
 // CRSTypes 表示坐标参考系类型
 type CRSTypes string
 
@@ -29,7 +34,109 @@ const (
 	EPSG102100  CRSTypes = EPSG3857
 	WebMercator CRSTypes = EPSG3857
 	WM          CRSTypes = EPSG3857
+
+	// CGCS2000，高斯-克吕格投影带见 CGCS2000GK
+	CGCS2000 CRSTypes = "CGCS2000"
+	EPSG4490 CRSTypes = CGCS2000
+
+	// 北京 1954，默认使用 EPSG:15918 三参数，见 SetDatumShift
+	Beijing1954 CRSTypes = "Beijing1954"
+	EPSG4214    CRSTypes = Beijing1954
+
+	// 西安 1980，须通过 SetDatumShift 设置转换参数
+	Xian1980 CRSTypes = "Xian1980"
+	EPSG4610 CRSTypes = Xian1980
+
+	// 香港 1980 及香港 1980 方格网
+	HK1980     CRSTypes = "HK1980"
+	EPSG4611   CRSTypes = HK1980
+	HK1980Grid CRSTypes = "HK1980Grid"
+	EPSG2326   CRSTypes = HK1980Grid
+
+	// TWD97 及其 TM2 投影（中央经线 121°E 与 119°E）
+	TWD97           CRSTypes = "TWD97"
+	EPSG3824        CRSTypes = TWD97
+	TWD97TM2        CRSTypes = "TWD97TM2"
+	EPSG3826        CRSTypes = TWD97TM2
+	TWD97TM2Zone119 CRSTypes = "TWD97TM2Zone119"
+	EPSG3825        CRSTypes = TWD97TM2Zone119
 )
 
-// Position 为经纬度或投影坐标 [x, y]，允许长度>=2
+// Position 为经纬度或投影坐标 [x, y]，允许长度>=2，
+// 如 [x, y, z] 或 [x, y, z, m]，转换时只改变前两维
 type Position []float64
+
+// ParseCRS 将字符串解析为已注册的坐标系，支持：
+//   - 坐标系名称及别名，不区分大小写，如 "wgs84"、"AMap"、"bmap"
+//   - EPSG 代码，如 "EPSG:4326"、"epsg3857"、"EPSG::900913"
+//   - OGC URN 与 URL，如 "urn:ogc:def:crs:EPSG::3857"、
+//     "http://www.opengis.net/def/crs/EPSG/0/4326"
+//
+// 无法识别时返回 ErrUnsupportedCRS 错误
+func ParseCRS(s string) (CRSTypes, error) {
+	name := strings.TrimSpace(s)
+	if name == "" {
+		return "", ErrEmptyCRS
+	}
+
+	// 快速路径：已注册的规范名称
+	if isRegisteredCRS(CRSTypes(name)) {
+		return CRSTypes(name), nil
+	}
+
+	if crs, ok := lookupCRSAlias(normalizeCRSName(name)); ok {
+		return crs, nil
+	}
+	return "", ErrUnsupportedCRS(CRSTypes(s))
+}
+
+// normalizeCRSName 将坐标系名称规范化为别名索引的键：
+// 转为大写，去掉 URN/URL 前缀及分隔符
+func normalizeCRSName(s string) string {
+	name := strings.ToUpper(strings.TrimSpace(s))
+
+	// urn:ogc:def:crs:EPSG:<版本>:<代码>，版本可为空
+	if rest, ok := strings.CutPrefix(name, "URN:OGC:DEF:CRS:"); ok {
+		parts := strings.Split(rest, ":")
+		if len(parts) >= 2 {
+			name = parts[0] + parts[len(parts)-1]
+		}
+	}
+
+	// http://www.opengis.net/def/crs/EPSG/<版本>/<代码>
+	for _, prefix := range []string{"HTTP://WWW.OPENGIS.NET/DEF/CRS/", "HTTPS://WWW.OPENGIS.NET/DEF/CRS/"} {
+		if rest, ok := strings.CutPrefix(name, prefix); ok {
+			parts := strings.Split(strings.Trim(rest, "/"), "/")
+			if len(parts) >= 2 {
+				name = parts[0] + parts[len(parts)-1]
+			}
+		}
+	}
+
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case ':', '_', '-', ' ', '/', '.':
+			return -1
+		}
+		return r
+	}, name)
+}
+
+// epsgCode 返回坐标系的 EPSG 代码，取名称或别名中第一个 EPSG 形式的代码，
+// 没有时返回 0
+func epsgCode(crs CRSTypes) int {
+	info, ok := LookupCRS(crs)
+	if !ok {
+		return 0
+	}
+	for _, name := range append([]string{string(info.Name)}, info.Aliases...) {
+		digits, ok := strings.CutPrefix(normalizeCRSName(name), "EPSG")
+		if !ok || digits == "" {
+			continue
+		}
+		if code, err := strconv.Atoi(digits); err == nil && code > 0 {
+			return code
+		}
+	}
+	return 0
+}
@@ -0,0 +1,208 @@
+package gcoord
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// CSVOptions 控制 TransformCSV 的列映射与输出方式
+type CSVOptions struct {
+	// LonColumn 经度（或 x）列，可为表头中的列名（不区分大小写）或从 0 开始的列序号，
+	// 为空时按常见列名自动识别，如 lon、lng、longitude、x
+	LonColumn string
+	// LatColumn 纬度（或 y）列，规则同 LonColumn，自动识别 lat、latitude、y 等
+	LatColumn string
+	// Append 为 true 时保留原坐标列，将转换结果追加为新列
+	Append bool
+	// AppendLonColumn、AppendLatColumn 追加列的列名，默认为原列名加 "_" 与目标坐标系
+	AppendLonColumn string
+	AppendLatColumn string
+	// Comma 字段分隔符，默认为 ','
+	Comma rune
+	// Options 转换选项，如 Strict、OutOfChina、Bounds，出错时错误的 Details 中记录行号
+	Options TransformOptions
+}
+
+// 自动识别的坐标列名
+var (
+	csvLonNames = []string{"lon", "lng", "long", "longitude", "x", "经度"}
+	csvLatNames = []string{"lat", "latitude", "y", "纬度"}
+)
+
+// TransformCSV 转换带表头的 CSV 中的坐标列，其余列原样保留。
+//
+// 坐标列均为空的行原样输出（追加模式下追加空列），
+// 坐标无法解析为数字时返回带行号的错误。
+//
+// 示例：
+//
+//	err := TransformCSV(in, out, BD09, WGS84, CSVOptions{LonColumn: "lng", LatColumn: "lat", Append: true})
+func TransformCSV(r io.Reader, w io.Writer, crsFrom, crsTo CRSTypes, opts CSVOptions) error {
+	crsFrom, err := validateCRS(crsFrom)
+	if err != nil {
+		return err
+	}
+	crsTo, err = validateCRS(crsTo)
+	if err != nil {
+		return err
+	}
+	topts := opts.Options
+	step := resolveStep(crsFrom, crsTo, &topts)
+	if step == nil {
+		return ErrNoConverter(crsFrom, crsTo)
+	}
+
+	cr := csv.NewReader(r)
+	cw := csv.NewWriter(w)
+	if opts.Comma != 0 {
+		cr.Comma = opts.Comma
+		cw.Comma = opts.Comma
+	}
+	// 允许各行列数不同，缺失的坐标列按空值处理
+	cr.FieldsPerRecord = -1
+
+	header, err := cr.Read()
+	if err == io.EOF {
+		return nil
+	}
+	if err != nil {
+		return errCSVParse(err)
+	}
+
+	lonIdx, err := findCSVColumn(header, opts.LonColumn, csvLonNames, "经度")
+	if err != nil {
+		return err
+	}
+	latIdx, err := findCSVColumn(header, opts.LatColumn, csvLatNames, "纬度")
+	if err != nil {
+		return err
+	}
+	if lonIdx == latIdx {
+		return &TransformError{
+			Type:    ErrInvalidInput,
+			Message: fmt.Sprintf("经度与纬度不能是同一列: %q", header[lonIdx]),
+			Details: map[string]interface{}{
+				"column": header[lonIdx],
+			},
+		}
+	}
+
+	if opts.Append {
+		lonName := opts.AppendLonColumn
+		if lonName == "" {
+			lonName = header[lonIdx] + "_" + string(crsTo)
+		}
+		latName := opts.AppendLatColumn
+		if latName == "" {
+			latName = header[latIdx] + "_" + string(crsTo)
+		}
+		header = append(header, lonName, latName)
+	}
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return errCSVParse(err)
+		}
+		line, _ := cr.FieldPos(0)
+
+		lonText, latText := csvField(record, lonIdx), csvField(record, latIdx)
+		outLon, outLat := "", ""
+		if lonText != "" || latText != "" {
+			lon, lonErr := strconv.ParseFloat(lonText, 64)
+			lat, latErr := strconv.ParseFloat(latText, 64)
+			if lonErr != nil || latErr != nil {
+				return &TransformError{
+					Type:    ErrInvalidInput,
+					Message: fmt.Sprintf("第 %d 行坐标无效: %q, %q", line, lonText, latText),
+					Details: map[string]interface{}{
+						"line": line,
+						"lon":  lonText,
+						"lat":  latText,
+					},
+				}
+			}
+			x, y, err := step(lon, lat, &topts)
+			if err != nil {
+				if te, ok := err.(*TransformError); ok && te.Details != nil {
+					te.Details["line"] = line
+				}
+				return err
+			}
+			outLon = strconv.FormatFloat(x, 'f', -1, 64)
+			outLat = strconv.FormatFloat(y, 'f', -1, 64)
+		}
+
+		if opts.Append {
+			record = append(record, outLon, outLat)
+		} else if outLon != "" {
+			record[lonIdx], record[latIdx] = outLon, outLat
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// findCSVColumn 按列名或序号查找坐标列，spec 为空时按候选列名自动识别
+func findCSVColumn(header []string, spec string, candidates []string, label string) (int, error) {
+	names := candidates
+	if spec != "" {
+		names = []string{spec}
+	}
+	for _, name := range names {
+		for i, h := range header {
+			if i == 0 {
+				// Excel 等导出的 UTF-8 CSV 以 BOM 开头
+				h = strings.TrimPrefix(h, "\ufeff")
+			}
+			if strings.EqualFold(strings.TrimSpace(h), name) {
+				return i, nil
+			}
+		}
+	}
+	if spec != "" {
+		if i, err := strconv.Atoi(spec); err == nil && i >= 0 && i < len(header) {
+			return i, nil
+		}
+	}
+	return 0, &TransformError{
+		Type:    ErrInvalidInput,
+		Message: fmt.Sprintf("CSV 中找不到%s列: %q", label, spec),
+		Details: map[string]interface{}{
+			"column": spec,
+			"header": header,
+		},
+	}
+}
+
+// csvField 返回指定列的值，列不存在时返回空串
+func csvField(record []string, i int) string {
+	if i < len(record) {
+		return strings.TrimSpace(record[i])
+	}
+	return ""
+}
+
+// errCSVParse 创建 CSV 解析失败错误
+func errCSVParse(err error) *TransformError {
+	return &TransformError{
+		Type:    ErrInvalidInput,
+		Message: fmt.Sprintf("CSV解析失败: %v", err),
+		Details: map[string]interface{}{
+			"original_error": err,
+		},
+	}
+}
@@ -0,0 +1,112 @@
+package gcoord
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func readCSV(t *testing.T, s string) [][]string {
+	t.Helper()
+	records, err := csv.NewReader(strings.NewReader(s)).ReadAll()
+	if err != nil {
+		t.Fatalf("invalid csv output: %v", err)
+	}
+	return records
+}
+
+func TestTransformCSVAutoDetect(t *testing.T) {
+	in := "name,lng,lat,note\n天安门,116.404,39.915,\"a,b\"\n空,,,x\n"
+	var out bytes.Buffer
+	if err := TransformCSV(strings.NewReader(in), &out, BD09, WGS84, CSVOptions{}); err != nil {
+		t.Fatalf("csv transform error: %v", err)
+	}
+	records := readCSV(t, out.String())
+	if len(records) != 3 || strings.Join(records[0], ",") != "name,lng,lat,note" {
+		t.Fatalf("unexpected output: %q", out.String())
+	}
+	if records[1][0] != "天安门" || records[1][3] != "a,b" {
+		t.Fatalf("other columns not preserved: %v", records[1])
+	}
+	lon, _ := strconv.ParseFloat(records[1][1], 64)
+	lat, _ := strconv.ParseFloat(records[1][2], 64)
+	want, _ := Transform(Position{116.404, 39.915}, BD09, WGS84)
+	if !approxPos(Position{lon, lat}, want, 1e-12) {
+		t.Fatalf("csv mismatch: got %v,%v want %v", lon, lat, want)
+	}
+	if records[2][1] != "" || records[2][2] != "" {
+		t.Fatalf("empty coordinates should be kept: %v", records[2])
+	}
+}
+
+func TestTransformCSVBOMHeader(t *testing.T) {
+	in := "\ufefflng,lat,name\n116.404,39.915,天安门\n"
+	var out bytes.Buffer
+	if err := TransformCSV(strings.NewReader(in), &out, BD09, WGS84, CSVOptions{}); err != nil {
+		t.Fatalf("csv transform error: %v", err)
+	}
+	records := readCSV(t, out.String())
+	lon, _ := strconv.ParseFloat(records[1][0], 64)
+	lat, _ := strconv.ParseFloat(records[1][1], 64)
+	want, _ := Transform(Position{116.404, 39.915}, BD09, WGS84)
+	if !approxPos(Position{lon, lat}, want, 1e-12) {
+		t.Fatalf("csv mismatch: got %v,%v want %v", lon, lat, want)
+	}
+}
+
+func TestTransformCSVAppendByIndex(t *testing.T) {
+	in := "id;X坐标;Y坐标\n1;116.397;39.908\n"
+	var out bytes.Buffer
+	err := TransformCSV(strings.NewReader(in), &out, WGS84, GCJ02, CSVOptions{
+		LonColumn: "1",
+		LatColumn: "y坐标",
+		Append:    true,
+		Comma:     ';',
+	})
+	if err != nil {
+		t.Fatalf("csv transform error: %v", err)
+	}
+	r := csv.NewReader(strings.NewReader(out.String()))
+	r.Comma = ';'
+	records, _ := r.ReadAll()
+	if strings.Join(records[0], ";") != "id;X坐标;Y坐标;X坐标_GCJ02;Y坐标_GCJ02" {
+		t.Fatalf("unexpected header: %v", records[0])
+	}
+	if records[1][1] != "116.397" || records[1][2] != "39.908" {
+		t.Fatalf("source columns should be kept in append mode: %v", records[1])
+	}
+	lon, _ := strconv.ParseFloat(records[1][3], 64)
+	lat, _ := strconv.ParseFloat(records[1][4], 64)
+	if !approxPos(Position{lon, lat}, WGS84ToGCJ02(Position{116.397, 39.908}), 1e-12) {
+		t.Fatalf("appended columns mismatch: %v", records[1])
+	}
+}
+
+func TestTransformCSVErrors(t *testing.T) {
+	var out bytes.Buffer
+	err := TransformCSV(strings.NewReader("a,b\n1,2\n"), &out, WGS84, GCJ02, CSVOptions{})
+	if GetErrorType(err) != ErrInvalidInput {
+		t.Fatalf("expect missing column error, got %v", err)
+	}
+
+	err = TransformCSV(strings.NewReader("lon,lat\n116,39\nabc,39\n"), &out, WGS84, GCJ02, CSVOptions{})
+	te, ok := err.(*TransformError)
+	if !ok || te.Details["line"] != 3 {
+		t.Fatalf("expect error at line 3, got %v", err)
+	}
+
+	// 转换选项：严格模式与境外坐标报错
+	for _, opts := range []TransformOptions{{Strict: true}, {OutOfChina: OutOfChinaError}} {
+		in := "lon,lat\n116,39\n-74.0,95\n"
+		if opts.OutOfChina == OutOfChinaError {
+			in = "lon,lat\n116,39\n-74.0,40.7\n"
+		}
+		err = TransformCSV(strings.NewReader(in), &out, WGS84, GCJ02, CSVOptions{Options: opts})
+		te, ok = err.(*TransformError)
+		if !ok || te.Type != ErrInvalidInput || te.Details["line"] != 3 {
+			t.Fatalf("%+v: expect error at line 3, got %v", opts, err)
+		}
+	}
+}
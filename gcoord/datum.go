@@ -0,0 +1,332 @@
+package gcoord
+
+import (
+	"fmt"
+	"math"
+)
+
+// Ellipsoid 参考椭球
+type Ellipsoid struct {
+	// A 长半轴（米）
+	A float64
+	// F 扁率
+	F float64
+}
+
+// 常用参考椭球
+var (
+	WGS84Ellipsoid     = Ellipsoid{A: WGS84A, F: WGS84F}
+	CGCS2000Ellipsoid  = Ellipsoid{A: CGCS2000A, F: CGCS2000F}
+	KrasovskyEllipsoid = Ellipsoid{A: GCJ02A, F: 1 / 298.3}    // 克拉索夫斯基椭球，北京 1954
+	IAG1975Ellipsoid   = Ellipsoid{A: 6378140, F: 1 / 298.257} // IAG-75 椭球，西安 1980
+	// 国际 1924（Hayford）椭球，香港 1980
+	International1924Ellipsoid = Ellipsoid{A: 6378388, F: 1 / 297.0}
+	// GRS80 椭球，TWD97
+	GRS80Ellipsoid = Ellipsoid{A: 6378137, F: 1 / 298.257222101}
+)
+
+// e2 第一偏心率的平方
+func (e Ellipsoid) e2() float64 {
+	return e.F * (2 - e.F)
+}
+
+// ToECEF 大地坐标转地心地固坐标，经纬度单位为度，高程与结果单位为米
+func (e Ellipsoid) ToECEF(lon, lat, h float64) (x, y, z float64) {
+	sinPhi, cosPhi := math.Sincos(lat * DegToRad)
+	sinLam, cosLam := math.Sincos(lon * DegToRad)
+	e2 := e.e2()
+	n := e.A / math.Sqrt(1-e2*sinPhi*sinPhi)
+	return (n + h) * cosPhi * cosLam,
+		(n + h) * cosPhi * sinLam,
+		(n*(1-e2) + h) * sinPhi
+}
+
+// FromECEF 地心地固坐标转大地坐标，迭代至纬度收敛（约 1e-14 弧度）
+func (e Ellipsoid) FromECEF(x, y, z float64) (lon, lat, h float64) {
+	e2 := e.e2()
+	p := math.Hypot(x, y)
+	phi := math.Atan2(z, p*(1-e2))
+	for i := 0; i < 10; i++ {
+		sinPhi, cosPhi := math.Sincos(phi)
+		n := e.A / math.Sqrt(1-e2*sinPhi*sinPhi)
+		h = p*cosPhi + z*sinPhi - e.A*e.A/n
+		next := math.Atan2(z, p*(1-e2*n/(n+h)))
+		if math.Abs(next-phi) < 1e-14 {
+			phi = next
+			break
+		}
+		phi = next
+	}
+	sinPhi, cosPhi := math.Sincos(phi)
+	h = p*cosPhi + z*sinPhi - e.A*math.Sqrt(1-e2*sinPhi*sinPhi)
+	return math.Atan2(y, x) * RadToDeg, phi * RadToDeg, h
+}
+
+// valid 检查椭球参数
+func (e Ellipsoid) valid() bool {
+	return e.A > 0 && e.F >= 0 && e.F < 1
+}
+
+// DatumShift 大地基准到 WGS84 的转换参数，由 Helmert 或 Molodensky 实现
+type DatumShift interface {
+	// toWGS84 将参考椭球 e 上的经纬度转换到 WGS84
+	toWGS84(e Ellipsoid, lon, lat float64) (float64, float64)
+	// fromWGS84 将 WGS84 经纬度转换到参考椭球 e 上
+	fromWGS84(e Ellipsoid, lon, lat float64) (float64, float64)
+}
+
+// Helmert 七参数（Bursa-Wolf）转换，在地心坐标上由源基准转换到 WGS84：
+//
+//	[X Y Z]ᵀ_WGS84 = [TX TY TZ]ᵀ + (1 + S·10⁻⁶)·R·[X Y Z]ᵀ
+//
+// 旋转参数默认采用位置矢量约定（EPSG:9606），CoordinateFrame 为 true 时采用
+// 坐标框架约定（EPSG:9607），两者旋转参数符号相反。只有平移时即为三参数转换。
+// 在经纬度之间转换时按大地高为 0 处理，往返误差在毫米级
+type Helmert struct {
+	// TX、TY、TZ 平移（米）
+	TX, TY, TZ float64
+	// RX、RY、RZ 旋转（角秒）
+	RX, RY, RZ float64
+	// S 尺度变化（ppm）
+	S float64
+	// CoordinateFrame 旋转参数是否采用坐标框架约定
+	CoordinateFrame bool
+}
+
+// rotation 返回弧度单位的旋转参数，已换算为位置矢量约定
+func (p Helmert) rotation() (rx, ry, rz float64) {
+	const arcsec = DegToRad / 3600
+	rx, ry, rz = p.RX*arcsec, p.RY*arcsec, p.RZ*arcsec
+	if p.CoordinateFrame {
+		return -rx, -ry, -rz
+	}
+	return rx, ry, rz
+}
+
+// forward 地心坐标由源基准转换到 WGS84
+func (p Helmert) forward(x, y, z float64) (float64, float64, float64) {
+	rx, ry, rz := p.rotation()
+	m := 1 + p.S*1e-6
+	return p.TX + m*(x-rz*y+ry*z),
+		p.TY + m*(rz*x+y-rx*z),
+		p.TZ + m*(-ry*x+rx*y+z)
+}
+
+// inverse 地心坐标由 WGS84 转换到源基准，旋转矩阵取转置，忽略旋转角的二阶项（亚毫米级）
+func (p Helmert) inverse(x, y, z float64) (float64, float64, float64) {
+	rx, ry, rz := p.rotation()
+	m := 1 + p.S*1e-6
+	x, y, z = (x-p.TX)/m, (y-p.TY)/m, (z-p.TZ)/m
+	return x + rz*y - ry*z,
+		-rz*x + y + rx*z,
+		ry*x - rx*y + z
+}
+
+func (p Helmert) toWGS84(e Ellipsoid, lon, lat float64) (float64, float64) {
+	lon, lat, _ = WGS84Ellipsoid.FromECEF(p.forward(e.ToECEF(lon, lat, 0)))
+	return lon, lat
+}
+
+func (p Helmert) fromWGS84(e Ellipsoid, lon, lat float64) (float64, float64) {
+	lon, lat, _ = e.FromECEF(p.inverse(WGS84Ellipsoid.ToECEF(lon, lat, 0)))
+	return lon, lat
+}
+
+// Molodensky 标准莫洛金斯基转换（EPSG:9604），直接在经纬度上改正三个平移参数与椭球差异，
+// 不经过地心坐标，与同参数的三参数 Helmert 转换相差通常在 1 米以内
+type Molodensky struct {
+	// DX、DY、DZ 平移（米）
+	DX, DY, DZ float64
+}
+
+func (p Molodensky) toWGS84(e Ellipsoid, lon, lat float64) (float64, float64) {
+	return molodensky(e, WGS84Ellipsoid, p.DX, p.DY, p.DZ, lon, lat)
+}
+
+func (p Molodensky) fromWGS84(e Ellipsoid, lon, lat float64) (float64, float64) {
+	return molodensky(WGS84Ellipsoid, e, -p.DX, -p.DY, -p.DZ, lon, lat)
+}
+
+// molodensky 将 src 椭球上高程为 0 的经纬度转换到 dst 椭球
+func molodensky(src, dst Ellipsoid, dx, dy, dz, lon, lat float64) (float64, float64) {
+	sinPhi, cosPhi := math.Sincos(lat * DegToRad)
+	sinLam, cosLam := math.Sincos(lon * DegToRad)
+	a, e2 := src.A, src.e2()
+	b := a * (1 - src.F)
+	da, df := dst.A-a, dst.F-src.F
+
+	w := 1 - e2*sinPhi*sinPhi
+	n := a / math.Sqrt(w)                  // 卯酉圈曲率半径
+	m := a * (1 - e2) / (w * math.Sqrt(w)) // 子午圈曲率半径
+
+	dPhi := (-dx*sinPhi*cosLam - dy*sinPhi*sinLam + dz*cosPhi +
+		da*n*e2*sinPhi*cosPhi/a + df*(m*a/b+n*b/a)*sinPhi*cosPhi) / m
+	dLam := (-dx*sinLam + dy*cosLam) / (n * cosPhi)
+	return lon + dLam*RadToDeg, lat + dPhi*RadToDeg
+}
+
+// Datum 大地基准：参考椭球及其到 WGS84 的转换参数
+type Datum struct {
+	Ellipsoid Ellipsoid
+	// ToWGS84 到 WGS84 的转换参数，为 nil 时视为与 WGS84 重合
+	ToWGS84 DatumShift
+}
+
+// datums 基于参考椭球的地理坐标系，用于 SetDatumShift 与 RegisterTransverseMercator，
+// 由 registryMutex 保护
+var datums = map[CRSTypes]Datum{
+	WGS84: {Ellipsoid: WGS84Ellipsoid},
+}
+
+// registerDatums 注册内置的大地基准
+func registerDatums() {
+	mustRegisterDatum(CRSInfo{
+		Name:        CGCS2000,
+		Description: "2000国家大地坐标系",
+		Aliases:     []string{"EPSG:4490", "China Geodetic Coordinate System 2000"},
+	}, Datum{Ellipsoid: CGCS2000Ellipsoid})
+	// EPSG:15918，精度为米级，精确转换应通过 SetDatumShift 设置测区参数
+	mustRegisterDatum(CRSInfo{
+		Name:        Beijing1954,
+		Description: "北京1954坐标系，克拉索夫斯基椭球",
+		Aliases:     []string{"EPSG:4214", "Beijing 1954", "BJ54"},
+	}, Datum{Ellipsoid: KrasovskyEllipsoid, ToWGS84: Helmert{TX: 15.8, TY: -154.4, TZ: -82.3}})
+
+	// 西安 1980 没有公开的通用转换参数，通过 SetDatumShift 设置后才能与其他坐标系转换
+	mustRegisterCRS(CRSInfo{
+		Name:        Xian1980,
+		Description: "西安1980坐标系，IAG-75椭球，需通过 SetDatumShift 设置转换参数",
+		Aliases:     []string{"EPSG:4610", "Xian 1980", "Xi'an 1980", "XA80"},
+	})
+	setDatum(Xian1980, Datum{Ellipsoid: IAG1975Ellipsoid})
+}
+
+// RegisterDatum 注册以 datum 为基准的地理坐标系及其与 WGS84 之间的转换函数，
+// 注册后即可经 WGS84 与 GCJ02、BD09 等坐标系相互转换。
+//
+// 示例：
+//
+//	err := RegisterDatum(CRSInfo{Name: "BJ54_LOCAL"}, Datum{
+//		Ellipsoid: KrasovskyEllipsoid,
+//		ToWGS84:   Helmert{TX: 12.6, TY: -155.2, TZ: -80.9, RX: 0.1, RY: -0.2, RZ: 0.3, S: 1.2},
+//	})
+func RegisterDatum(info CRSInfo, datum Datum) error {
+	if !datum.Ellipsoid.valid() {
+		return errInvalidEllipsoid(datum.Ellipsoid)
+	}
+	info.Projected = false
+	if err := RegisterCRS(info); err != nil {
+		return err
+	}
+	setDatum(info.Name, datum)
+	return registerDatumSteps(info.Name, datum)
+}
+
+// SetDatumShift 设置地理坐标系到 WGS84 的转换参数，替换已有参数，
+// 如按测区求得的七参数。crs 须为内置的 CGCS2000、Beijing1954、Xian1980
+// 或通过 RegisterDatum 注册的坐标系，基于它的投影坐标系随之生效
+func SetDatumShift(crs CRSTypes, shift DatumShift) error {
+	crs, err := validateCRS(crs)
+	if err != nil {
+		return err
+	}
+	datum, ok := lookupDatum(crs)
+	if !ok || crs == WGS84 {
+		return &TransformError{
+			Type:    ErrInvalidCRS,
+			Message: fmt.Sprintf("坐标系 %s 不能设置基准转换参数", crs),
+			Details: map[string]interface{}{
+				"crs": crs,
+			},
+		}
+	}
+	datum.ToWGS84 = shift
+	setDatum(crs, datum)
+	return registerDatumSteps(crs, datum)
+}
+
+// RegisterTransverseMercator 注册横轴墨卡托（高斯-克吕格）投影坐标系，
+// 使用地理坐标系 base 的参考椭球，base 须为 WGS84 或 SetDatumShift 所列的坐标系。
+//
+// 示例：
+//
+//	err := RegisterTransverseMercator(CRSInfo{Name: "BJ54_3GK_39"}, Beijing1954, TransverseMercator{
+//		CentralMeridian: 117,
+//		FalseEasting:    39500000,
+//	})
+func RegisterTransverseMercator(info CRSInfo, base CRSTypes, p TransverseMercator) error {
+	base, err := validateCRS(base)
+	if err != nil {
+		return err
+	}
+	datum, ok := lookupDatum(base)
+	if !ok {
+		return &TransformError{
+			Type:    ErrInvalidCRS,
+			Message: fmt.Sprintf("坐标系 %s 不是基于参考椭球的地理坐标系", base),
+			Details: map[string]interface{}{
+				"crs": base,
+			},
+		}
+	}
+	info.Projected = true
+	if err := RegisterCRS(info); err != nil {
+		return err
+	}
+	forward, inverse := newTransverseMercator(datum.Ellipsoid, p).steps()
+	if err := registerEdge(base, info.Name, converterEdge{step: forward, cost: DefaultConverterCost}); err != nil {
+		return err
+	}
+	return registerEdge(info.Name, base, converterEdge{step: inverse, cost: DefaultConverterCost})
+}
+
+// registerDatumSteps 注册或替换地理坐标系与 WGS84 之间的转换函数
+func registerDatumSteps(crs CRSTypes, datum Datum) error {
+	toWGS84, fromWGS84 := pointStep(identityStep), pointStep(identityStep)
+	if shift := datum.ToWGS84; shift != nil {
+		e := datum.Ellipsoid
+		toWGS84 = stepOf(func(lon, lat float64) (float64, float64) { return shift.toWGS84(e, lon, lat) })
+		fromWGS84 = stepOf(func(lon, lat float64) (float64, float64) { return shift.fromWGS84(e, lon, lat) })
+	}
+	if err := registerEdge(crs, WGS84, converterEdge{step: toWGS84, cost: DefaultConverterCost}); err != nil {
+		return err
+	}
+	return registerEdge(WGS84, crs, converterEdge{step: fromWGS84, cost: DefaultConverterCost})
+}
+
+// mustRegisterTransverseMercator 注册内置横轴墨卡托投影，失败时 panic
+func mustRegisterTransverseMercator(info CRSInfo, base CRSTypes, p TransverseMercator) {
+	if err := RegisterTransverseMercator(info, base, p); err != nil {
+		panic(err)
+	}
+}
+
+// mustRegisterDatum 注册内置大地基准，失败时 panic
+func mustRegisterDatum(info CRSInfo, datum Datum) {
+	if err := RegisterDatum(info, datum); err != nil {
+		panic(err)
+	}
+}
+
+func setDatum(crs CRSTypes, datum Datum) {
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+	datums[crs] = datum
+}
+
+func lookupDatum(crs CRSTypes) (Datum, bool) {
+	registryMutex.RLock()
+	defer registryMutex.RUnlock()
+	datum, ok := datums[crs]
+	return datum, ok
+}
+
+func errInvalidEllipsoid(e Ellipsoid) *TransformError {
+	return &TransformError{
+		Type:    ErrInvalidInput,
+		Message: fmt.Sprintf("无效的参考椭球: a=%v, f=%v", e.A, e.F),
+		Details: map[string]interface{}{
+			"ellipsoid": e,
+		},
+	}
+}
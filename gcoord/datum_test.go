@@ -0,0 +1,120 @@
+package gcoord
+
+import "testing"
+
+func TestEllipsoidECEF(t *testing.T) {
+	// EPSG Guidance Note 7-2 地心坐标算例：53°48'33.820"N, 2°07'46.380"E, h=73 米
+	lon := 2 + 7/60.0 + 46.38/3600
+	lat := 53 + 48/60.0 + 33.82/3600
+	x, y, z := WGS84Ellipsoid.ToECEF(lon, lat, 73)
+	if !approx(x, 3771793.968, 1e-3) || !approx(y, 140253.342, 1e-3) || !approx(z, 5124304.349, 1e-3) {
+		t.Fatalf("ToECEF = %.3f, %.3f, %.3f", x, y, z)
+	}
+	l, b, h := WGS84Ellipsoid.FromECEF(x, y, z)
+	if !approx(l, lon, 1e-12) || !approx(b, lat, 1e-12) || !approx(h, 73, 1e-6) {
+		t.Fatalf("FromECEF = %v, %v, %v", l, b, h)
+	}
+}
+
+func TestHelmert(t *testing.T) {
+	// EPSG Guidance Note 7-2 七参数算例（WGS 72 -> WGS 84）
+	pv := Helmert{TZ: 4.5, RZ: 0.554, S: 0.219}
+	cf := Helmert{TZ: 4.5, RZ: -0.554, S: 0.219, CoordinateFrame: true}
+	for name, p := range map[string]Helmert{"position vector": pv, "coordinate frame": cf} {
+		x, y, z := p.forward(3657660.66, 255768.55, 5201382.11)
+		if !approx(x, 3657660.78, 0.01) || !approx(y, 255778.43, 0.01) || !approx(z, 5201387.75, 0.01) {
+			t.Errorf("%s: forward = %.3f, %.3f, %.3f", name, x, y, z)
+		}
+		x, y, z = p.inverse(x, y, z)
+		if !approx(x, 3657660.66, 1e-4) || !approx(y, 255768.55, 1e-4) || !approx(z, 5201382.11, 1e-4) {
+			t.Errorf("%s: inverse = %.6f, %.6f, %.6f", name, x, y, z)
+		}
+	}
+
+	// 三参数时 Molodensky 与 Helmert 结果相近；二维转换忽略高程，往返误差在毫米级
+	h := Helmert{TX: 15.8, TY: -154.4, TZ: -82.3}
+	m := Molodensky{DX: 15.8, DY: -154.4, DZ: -82.3}
+	lon, lat := 116.397, 39.908
+	hl, hb := h.toWGS84(KrasovskyEllipsoid, lon, lat)
+	ml, mb := m.toWGS84(KrasovskyEllipsoid, lon, lat)
+	if !approx(hl, ml, 1e-5) || !approx(hb, mb, 1e-5) {
+		t.Fatalf("Helmert %v, %v vs Molodensky %v, %v", hl, hb, ml, mb)
+	}
+	if l, b := h.fromWGS84(KrasovskyEllipsoid, hl, hb); !approx(l, lon, 1e-8) || !approx(b, lat, 1e-8) {
+		t.Fatalf("Helmert roundtrip = %v, %v", l, b)
+	}
+	if l, b := m.fromWGS84(KrasovskyEllipsoid, ml, mb); !approx(l, lon, 1e-7) || !approx(b, lat, 1e-7) {
+		t.Fatalf("Molodensky roundtrip = %v, %v", l, b)
+	}
+}
+
+func TestLegacyDatums(t *testing.T) {
+	p := Position{116.397, 39.908}
+
+	// 北京 1954 经 WGS84 转换到 GCJ02
+	gcj, err := Transform(p, Beijing1954, GCJ02)
+	if err != nil {
+		t.Fatalf("Beijing1954 -> GCJ02: %v", err)
+	}
+	back, err := Transform(gcj, GCJ02, Beijing1954)
+	if err != nil || !approxPos(back, p, TestPrecisionRoundtrip) {
+		t.Fatalf("GCJ02 -> Beijing1954 = %v, %v", back, err)
+	}
+	if crs, _ := ParseCRS("EPSG:4214"); crs != Beijing1954 {
+		t.Fatalf("ParseCRS(EPSG:4214) = %v", crs)
+	}
+
+	// 西安 1980 没有默认参数
+	if _, err := Transform(p, Xian1980, WGS84); err == nil {
+		t.Fatalf("expected error for Xian1980 without datum shift")
+	}
+	if err := SetDatumShift(WGS84, Helmert{}); err == nil {
+		t.Fatalf("expected error setting WGS84 datum shift")
+	}
+	if err := SetDatumShift(GCJ02, Helmert{}); err == nil {
+		t.Fatalf("expected error setting GCJ02 datum shift")
+	}
+}
+
+func TestRegisterDatum(t *testing.T) {
+	const geo, grid CRSTypes = "TEST_DATUM", "TEST_DATUM_3GK_39"
+	t.Cleanup(func() { unregisterCRS(geo, grid) })
+	if err := RegisterDatum(CRSInfo{Name: geo}, Datum{Ellipsoid: IAG1975Ellipsoid}); err != nil {
+		t.Fatalf("RegisterDatum: %v", err)
+	}
+	if err := RegisterTransverseMercator(CRSInfo{Name: grid}, geo, TransverseMercator{
+		CentralMeridian: 117,
+		FalseEasting:    39500000,
+	}); err != nil {
+		t.Fatalf("RegisterTransverseMercator: %v", err)
+	}
+	if err := RegisterTransverseMercator(CRSInfo{Name: "TEST_GCJ_TM"}, GCJ02, TransverseMercator{}); err == nil {
+		t.Fatalf("expected error for non-ellipsoidal base")
+	}
+	if err := RegisterDatum(CRSInfo{Name: "TEST_BAD_ELLIPSOID"}, Datum{}); err == nil {
+		t.Fatalf("expected error for invalid ellipsoid")
+	}
+
+	p := Position{116.397, 39.908}
+	xy, err := Transform(p, WGS84, grid)
+	if err != nil {
+		t.Fatalf("WGS84 -> grid: %v", err)
+	}
+
+	// 设置参数后已缓存的转换路径失效，投影坐标系随之变化
+	shift := Helmert{TX: 24, TY: -123, TZ: -94, RX: 0.02, RY: -0.25, RZ: 0.13, S: 1.1}
+	if err := SetDatumShift(geo, shift); err != nil {
+		t.Fatalf("SetDatumShift: %v", err)
+	}
+	shifted, err := Transform(p, WGS84, grid)
+	if err != nil {
+		t.Fatalf("WGS84 -> grid after shift: %v", err)
+	}
+	if approx(shifted[0], xy[0], 1) && approx(shifted[1], xy[1], 1) {
+		t.Fatalf("datum shift not applied: %v vs %v", shifted, xy)
+	}
+	back, err := Transform(shifted, grid, WGS84)
+	if err != nil || !approxPos(back, p, 1e-7) {
+		t.Fatalf("grid -> WGS84 = %v, %v", back, err)
+	}
+}
@@ -0,0 +1,141 @@
+package gcoord
+
+import "math"
+
+// WGS84ToECEF WGS84 [lon, lat, h] -> 地心地固坐标 [x, y, z]，缺少高程时按 0 处理，
+// 第四维起的额外维度保持不变
+func WGS84ToECEF(p Position) Position {
+	x, y, z := WGS84Ellipsoid.ToECEF(p[0], p[1], height(p))
+	return withXYZ(p, x, y, z)
+}
+
+// ECEFToWGS84 地心地固坐标 [x, y, z] -> WGS84 [lon, lat, h]，缺少 z 时按 0 处理
+func ECEFToWGS84(p Position) Position {
+	lon, lat, h := WGS84Ellipsoid.FromECEF(p[0], p[1], height(p))
+	return withXYZ(p, lon, lat, h)
+}
+
+// ENU 以 WGS84 原点为中心的东-北-天（East-North-Up）局部坐标系，单位为米，
+// 用于无人机、机器人等局部坐标与地图坐标之间的转换
+//
+// 示例：
+//
+//	enu := NewENU(Position{116.397, 39.908, 50})
+//	bd := enu.ToBD09(Position{120.5, -35.2, 12}) // 原点以东 120.5 米、以南 35.2 米、高 12 米
+type ENU struct {
+	origin     Position
+	x0, y0, z0 float64
+	// 原点经纬度的正弦与余弦
+	sinLon, cosLon, sinLat, cosLat float64
+}
+
+// NewENU 创建以 WGS84 原点 [lon, lat, h] 为中心的 ENU 坐标系，缺少高程时按 0 处理
+func NewENU(origin Position) *ENU {
+	e := &ENU{origin: Position{origin[0], origin[1], height(origin)}}
+	e.x0, e.y0, e.z0 = WGS84Ellipsoid.ToECEF(e.origin[0], e.origin[1], e.origin[2])
+	e.sinLon, e.cosLon = math.Sincos(origin[0] * DegToRad)
+	e.sinLat, e.cosLat = math.Sincos(origin[1] * DegToRad)
+	return e
+}
+
+// Origin 返回原点 [lon, lat, h]
+func (e *ENU) Origin() Position {
+	return append(Position(nil), e.origin...)
+}
+
+// FromECEF 地心地固坐标 [x, y, z] -> [east, north, up]，缺少 z 时按 0 处理
+func (e *ENU) FromECEF(p Position) Position {
+	east, north, up := e.fromECEF(p[0], p[1], height(p))
+	return withXYZ(p, east, north, up)
+}
+
+// ToECEF [east, north, up] -> 地心地固坐标 [x, y, z]，缺少 up 时按 0 处理
+func (e *ENU) ToECEF(p Position) Position {
+	x, y, z := e.toECEF(p[0], p[1], height(p))
+	return withXYZ(p, x, y, z)
+}
+
+// FromWGS84 WGS84 [lon, lat, h] -> [east, north, up]，缺少高程时按 0 处理
+func (e *ENU) FromWGS84(p Position) Position {
+	east, north, up := e.fromECEF(WGS84Ellipsoid.ToECEF(p[0], p[1], height(p)))
+	return withXYZ(p, east, north, up)
+}
+
+// ToWGS84 [east, north, up] -> WGS84 [lon, lat, h]
+func (e *ENU) ToWGS84(p Position) Position {
+	lon, lat, h := WGS84Ellipsoid.FromECEF(e.toECEF(p[0], p[1], height(p)))
+	return withXYZ(p, lon, lat, h)
+}
+
+// ToGCJ02 [east, north, up] -> GCJ02 [lon, lat, h]，用于在高德地图上显示
+func (e *ENU) ToGCJ02(p Position) Position {
+	return WGS84ToGCJ02(e.ToWGS84(p))
+}
+
+// ToBD09 [east, north, up] -> BD09 [lon, lat, h]，用于在百度地图上显示
+func (e *ENU) ToBD09(p Position) Position {
+	return GCJ02ToBD09(e.ToGCJ02(p))
+}
+
+func (e *ENU) fromECEF(x, y, z float64) (east, north, up float64) {
+	dx, dy, dz := x-e.x0, y-e.y0, z-e.z0
+	east = -e.sinLon*dx + e.cosLon*dy
+	north = -e.sinLat*e.cosLon*dx - e.sinLat*e.sinLon*dy + e.cosLat*dz
+	up = e.cosLat*e.cosLon*dx + e.cosLat*e.sinLon*dy + e.sinLat*dz
+	return east, north, up
+}
+
+func (e *ENU) toECEF(east, north, up float64) (x, y, z float64) {
+	x = e.x0 - e.sinLon*east - e.sinLat*e.cosLon*north + e.cosLat*e.cosLon*up
+	y = e.y0 + e.cosLon*east - e.sinLat*e.sinLon*north + e.cosLat*e.sinLon*up
+	z = e.z0 + e.cosLat*north + e.sinLat*up
+	return x, y, z
+}
+
+// RegisterENU 将以 origin 为原点的 ENU 坐标系注册为投影坐标系，
+// 之后即可通过 Transform 在 [east, north] 与 GCJ02、BD09 等坐标系之间转换 GeoJSON、WKT 等数据。
+// 转换按 up 为 0 计算，第三维原样保留
+//
+// 示例：
+//
+//	err := RegisterENU(CRSInfo{Name: "SITE_A"}, Position{116.397, 39.908, 50})
+//	gcj, err := Transform(Position{120.5, -35.2}, "SITE_A", GCJ02)
+func RegisterENU(info CRSInfo, origin Position) error {
+	if len(origin) < 2 {
+		return ErrInvalidPosition
+	}
+	info.Projected = true
+	if err := RegisterCRS(info); err != nil {
+		return err
+	}
+	e := NewENU(origin)
+	toWGS84 := func(east, north float64) (float64, float64) {
+		lon, lat, _ := WGS84Ellipsoid.FromECEF(e.toECEF(east, north, 0))
+		return lon, lat
+	}
+	// 反算按原点高程取点，与正算相差地球曲率引起的高差，距原点 10 千米内水平误差约 1 厘米
+	fromWGS84 := func(lon, lat float64) (float64, float64) {
+		east, north, _ := e.fromECEF(WGS84Ellipsoid.ToECEF(lon, lat, e.origin[2]))
+		return east, north
+	}
+	if err := registerEdge(info.Name, WGS84, converterEdge{step: stepOf(toWGS84), cost: DefaultConverterCost}); err != nil {
+		return err
+	}
+	return registerEdge(WGS84, info.Name, converterEdge{step: stepOf(fromWGS84), cost: DefaultConverterCost})
+}
+
+// height 返回第三维，缺少时为 0
+func height(p Position) float64 {
+	if len(p) > 2 {
+		return p[2]
+	}
+	return 0
+}
+
+// withXYZ 复制 p 并替换前三维，p 不足三维时补齐，第四维起的额外维度保持不变
+func withXYZ(p Position, x, y, z float64) Position {
+	out := make(Position, max(len(p), 3))
+	copy(out[3:], p[min(len(p), 3):])
+	out[0], out[1], out[2] = x, y, z
+	return out
+}
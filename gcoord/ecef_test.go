@@ -0,0 +1,82 @@
+package gcoord
+
+import "testing"
+
+func TestWGS84ECEF(t *testing.T) {
+	p := Position{116.397, 39.908, 50, 7}
+	xyz := WGS84ToECEF(p)
+	if len(xyz) != 4 || xyz[3] != 7 {
+		t.Fatalf("extra dimension lost: %v", xyz)
+	}
+	back := ECEFToWGS84(xyz)
+	if !approxPos(back, p, 1e-12) || !approx(back[2], 50, 1e-6) || back[3] != 7 {
+		t.Fatalf("roundtrip = %v", back)
+	}
+	if got := WGS84ToECEF(Position{0, 0}); !approx(got[0], WGS84A, 1e-6) || got[1] != 0 || got[2] != 0 {
+		t.Fatalf("equator ECEF = %v", got)
+	}
+	// 二维输入按 z 为 0 处理
+	if got := ECEFToWGS84(Position{WGS84A, 0}); !approxPos(got, Position{0, 0, 0}, 1e-9) {
+		t.Fatalf("2D ECEF = %v", got)
+	}
+	if got := NewENU(Position{0, 0}).FromECEF(Position{WGS84A, 0}); !approxPos(got, Position{0, 0, 0}, 1e-9) {
+		t.Fatalf("2D ENU.FromECEF = %v", got)
+	}
+}
+
+func TestENU(t *testing.T) {
+	// EPSG Guidance Note 7-2 地心/站心坐标算例：原点 55°N, 5°E, h=200 米
+	enu := NewENU(Position{5, 55, 200})
+	got := enu.FromECEF(Position{3771793.968, 140253.342, 5124304.349})
+	if !approx(got[0], -189013.869, 1e-3) || !approx(got[1], -128642.040, 1e-3) || !approx(got[2], -4220.171, 1e-3) {
+		t.Fatalf("FromECEF = %.3f, %.3f, %.3f", got[0], got[1], got[2])
+	}
+	if xyz := enu.ToECEF(got); !approx(xyz[0], 3771793.968, 1e-6) || !approx(xyz[2], 5124304.349, 1e-6) {
+		t.Fatalf("ToECEF = %v", xyz)
+	}
+
+	origin := Position{116.397, 39.908, 50}
+	enu = NewENU(origin)
+	if o := enu.FromWGS84(origin); !approx(o[0], 0, 1e-6) || !approx(o[1], 0, 1e-6) || !approx(o[2], 0, 1e-6) {
+		t.Fatalf("origin in ENU = %v", o)
+	}
+
+	// 原点正上方 100 米
+	if up := enu.FromWGS84(Position{116.397, 39.908, 150}); !approx(up[0], 0, 1e-6) || !approx(up[1], 0, 1e-6) || !approx(up[2], 100, 1e-6) {
+		t.Fatalf("up = %v", up)
+	}
+
+	local := Position{120.5, -35.2, 12}
+	wgs := enu.ToWGS84(local)
+	if back := enu.FromWGS84(wgs); !approx(back[0], local[0], 1e-6) || !approx(back[1], local[1], 1e-6) || !approx(back[2], local[2], 1e-6) {
+		t.Fatalf("ENU roundtrip = %v", back)
+	}
+	if gcj, want := enu.ToGCJ02(local), WGS84ToGCJ02(wgs); !approxPos(gcj, want, 1e-12) || gcj[2] != wgs[2] {
+		t.Fatalf("ToGCJ02 = %v, want %v", gcj, want)
+	}
+	if bd, want := enu.ToBD09(local), GCJ02ToBD09(WGS84ToGCJ02(wgs)); !approxPos(bd, want, 1e-12) {
+		t.Fatalf("ToBD09 = %v, want %v", bd, want)
+	}
+}
+
+func TestRegisterENU(t *testing.T) {
+	const site CRSTypes = "TEST_ENU_SITE"
+	t.Cleanup(func() { unregisterCRS(site) })
+	origin := Position{116.397, 39.908, 50}
+	if err := RegisterENU(CRSInfo{Name: site}, origin); err != nil {
+		t.Fatalf("RegisterENU: %v", err)
+	}
+
+	local := Position{1200.5, -350.2}
+	gcj, err := Transform(local, site, GCJ02)
+	if err != nil {
+		t.Fatalf("ENU -> GCJ02: %v", err)
+	}
+	if want := NewENU(origin).ToGCJ02(local); !approxPos(gcj, want, 1e-9) {
+		t.Fatalf("ENU -> GCJ02 = %v, want %v", gcj, want)
+	}
+	back, err := Transform(gcj, GCJ02, site)
+	if err != nil || !approx(back[0], local[0], 0.01) || !approx(back[1], local[1], 0.01) {
+		t.Fatalf("GCJ02 -> ENU = %v, %v", back, err)
+	}
+}
@@ -1,42 +1,71 @@
 package gcoord
 
-import "math"
+import (
+	"fmt"
+	"math"
+)
 
 // 使用 constants.go 中定义的常量
 
 // EPSG3857ToWGS84 WebMercator -> WGS84
 func EPSG3857ToWGS84(xy Position) Position {
-	return Position{
-		(xy[0] * RadToDeg) / WGS84A,
-		(math.Pi*0.5 - 2.0*math.Atan(math.Exp(-xy[1]/WGS84A))) * RadToDeg,
-	}
+	lon, lat := epsg3857ToWGS84(xy[0], xy[1])
+	return withXY(xy, lon, lat)
 }
 
 // WGS84ToEPSG3857 WGS84 -> WebMercator
 func WGS84ToEPSG3857(lonLat Position) Position {
-	adjusted := lonLat[0]
-	if math.Abs(lonLat[0]) > 180 {
-		if lonLat[0] < 0 {
-			adjusted = lonLat[0] + 360
-		} else {
-			adjusted = lonLat[0] - 360
+	x, y := wgs84ToEPSG3857(lonLat[0], lonLat[1])
+	return withXY(lonLat, x, y)
+}
+
+func epsg3857ToWGS84(x, y float64) (float64, float64) {
+	return (x * RadToDeg) / WGS84A,
+		(math.Pi*0.5 - 2.0*math.Atan(math.Exp(-y/WGS84A))) * RadToDeg
+}
+
+func wgs84ToEPSG3857(lon, lat float64) (float64, float64) {
+	x, y := mercator(lon, lat)
+	return clampExtent(x), clampExtent(y)
+}
+
+// wgs84ToEPSG3857Step o.Bounds 为 BoundsError 时，超出 Web 墨卡托范围返回错误
+func wgs84ToEPSG3857Step(lon, lat float64, o *TransformOptions) (float64, float64, error) {
+	x, y := mercator(lon, lat)
+	if o.Bounds == BoundsError && !(math.Abs(x) <= MaxExtent && math.Abs(y) <= MaxExtent) {
+		return x, y, &TransformError{
+			Type:    ErrInvalidInput,
+			Message: fmt.Sprintf("坐标超出 Web 墨卡托范围: [%v, %v]", lon, lat),
+			Details: map[string]interface{}{
+				"position": Position{lon, lat},
+			},
 		}
 	}
-	xy := Position{
-		WGS84A * adjusted * DegToRad,
-		WGS84A * math.Log(math.Tan(math.Pi*0.25+0.5*lonLat[1]*DegToRad)),
-	}
-	if xy[0] > MaxExtent {
-		xy[0] = MaxExtent
-	}
-	if xy[0] < -MaxExtent {
-		xy[0] = -MaxExtent
+	return clampExtent(x), clampExtent(y), nil
+}
+
+// mercator 未限制范围的 Web 墨卡托投影，经度超出 ±180 时平移一周
+func mercator(lon, lat float64) (float64, float64) {
+	adjusted := lon
+	if math.Abs(lon) > 180 {
+		if lon < 0 {
+			adjusted = lon + 360
+		} else {
+			adjusted = lon - 360
+		}
 	}
-	if xy[1] > MaxExtent {
-		xy[1] = MaxExtent
+	x := WGS84A * adjusted * DegToRad
+	y := WGS84A * math.Log(math.Tan(math.Pi*0.25+0.5*lat*DegToRad))
+	return x, y
+}
+
+// clampExtent 将投影坐标限制在 Web 墨卡托范围内
+func clampExtent(v float64) float64 {
+	if v > MaxExtent {
+		return MaxExtent
 	}
-	if xy[1] < -MaxExtent {
-		xy[1] = -MaxExtent
+	if v < -MaxExtent {
+		return -MaxExtent
 	}
-	return xy
+	return v
 }
@@ -49,6 +49,41 @@ func ErrUnsupportedCRS(crs CRSTypes) *TransformError {
 	}
 }
 
+// ErrCRSAlreadyRegistered 创建坐标系重复注册错误
+func ErrCRSAlreadyRegistered(crs CRSTypes) *TransformError {
+	return &TransformError{
+		Type:    ErrInvalidCRS,
+		Message: fmt.Sprintf("坐标系已注册: %s", crs),
+		Details: map[string]interface{}{
+			"crs": crs,
+		},
+	}
+}
+
+// ErrNoConverter 创建缺少转换路径错误
+func ErrNoConverter(from, to CRSTypes) *TransformError {
+	return &TransformError{
+		Type:    ErrInvalidCRS,
+		Message: fmt.Sprintf("不支持从 %s 转换到 %s", from, to),
+		Details: map[string]interface{}{
+			"from": from,
+			"to":   to,
+		},
+	}
+}
+
+// errNilConverter 创建转换函数为空错误
+func errNilConverter(from, to CRSTypes) *TransformError {
+	return &TransformError{
+		Type:    ErrInvalidInput,
+		Message: "转换函数不能为空",
+		Details: map[string]interface{}{
+			"from": from,
+			"to":   to,
+		},
+	}
+}
+
 // ErrJSONParseFailed 创建JSON解析失败错误
 func ErrJSONParseFailed(err error) *TransformError {
 	return &TransformError{
@@ -60,6 +95,26 @@ func ErrJSONParseFailed(err error) *TransformError {
 	}
 }
 
+// withPath 在错误的 JSON 路径前添加片段，如 ".features[3]"、"[0]"。
+// Details 为 nil 的预定义错误不做修改
+func withPath(err error, segment string) error {
+	if te, ok := err.(*TransformError); ok && te.Details != nil {
+		path, _ := te.Details["path"].(string)
+		te.Details["path"] = segment + path
+	}
+	return err
+}
+
+// atRoot 为错误的 JSON 路径添加根 "$"，并在消息中注明路径
+func atRoot(err error) error {
+	if te, ok := err.(*TransformError); ok && te.Details != nil {
+		path, _ := te.Details["path"].(string)
+		te.Details["path"] = "$" + path
+		te.Message = fmt.Sprintf("%s，路径 $%s", te.Message, path)
+	}
+	return err
+}
+
 // IsTransformError 检查是否为转换错误
 func IsTransformError(err error) bool {
 	_, ok := err.(*TransformError)
@@ -1,6 +1,9 @@
 package gcoord
 
-import "math"
+import (
+	"fmt"
+	"math"
+)
 
 // 使用 constants.go 中定义的常量
 
@@ -39,32 +42,133 @@ func delta(lon, lat float64) (float64, float64) {
 	return dLon, dLat
 }
 
-// WGS84ToGCJ02 按 JS 逻辑转换（中国境外不变）
+// WGS84ToGCJ02 按 JS 逻辑转换，中国境外（按简化国界判断，含港澳台）不变
 func WGS84ToGCJ02(coord Position) Position {
-	lon, lat := coord[0], coord[1]
-	if !isInChinaBbox(lon, lat) {
-		return Position{lon, lat}
+	lon, lat := wgs84ToGCJ02(coord[0], coord[1])
+	return withXY(coord, lon, lat)
+}
+
+// GCJ02ToWGS84 使用迭代反解，精度为 IterationPrecision，
+// 不收敛时返回最后一次迭代的结果。需要控制精度或获知是否收敛时使用 TransformWithOptions
+func GCJ02ToWGS84(coord Position) Position {
+	lon, lat := gcj02ToWGS84(coord[0], coord[1])
+	return withXY(coord, lon, lat)
+}
+
+func wgs84ToGCJ02(lon, lat float64) (float64, float64) {
+	lon, lat, _ = wgs84ToGCJ02Step(lon, lat, defaultOptions)
+	return lon, lat
+}
+
+// wgs84ToGCJ02Step WGS84 转 GCJ02，o.ExcludeRegions 中的港澳台区域不加偏，
+// 境外坐标按 o.OutOfChina 处理
+func wgs84ToGCJ02Step(lon, lat float64, o *TransformOptions) (float64, float64, error) {
+	if ok, err := offsetApplies(lon, lat, o); !ok {
+		return lon, lat, err
 	}
 	dLon, dLat := delta(lon, lat)
-	return Position{lon + dLon, lat + dLat}
+	return lon + dLon, lat + dLat, nil
 }
 
-// GCJ02ToWGS84 使用迭代反解
-func GCJ02ToWGS84(coord Position) Position {
-	lon, lat := coord[0], coord[1]
-	if !isInChinaBbox(lon, lat) {
-		return Position{lon, lat}
+func gcj02ToWGS84(lon, lat float64) (float64, float64) {
+	lon, lat, _ = gcj02ToWGS84Step(lon, lat, defaultOptions)
+	return lon, lat
+}
+
+// gcj02ToWGS84Step 求解 p + delta(p) = (lon, lat)，先用不动点迭代，收敛变慢时改用牛顿迭代。
+//
+// 残差的两个分量都不超过 o.InversePrecision 时收敛，
+// 超过 o.MaxIterations 次仍未收敛时返回 ErrTransformFailed 错误。
+// NaN、Inf 等不在加偏区域内的输入原样返回，不会进入迭代。
+func gcj02ToWGS84Step(lon, lat float64, o *TransformOptions) (float64, float64, error) {
+	if ok, err := offsetApplies(lon, lat, o); !ok {
+		return lon, lat, err
 	}
-	wgsLon, wgsLat := lon, lat
-	temp := WGS84ToGCJ02(Position{wgsLon, wgsLat})
-	dx := temp[0] - lon
-	dy := temp[1] - lat
-	for math.Abs(dx) > IterationPrecision || math.Abs(dy) > IterationPrecision {
-		wgsLon -= dx
-		wgsLat -= dy
-		temp = WGS84ToGCJ02(Position{wgsLon, wgsLat})
-		dx = temp[0] - lon
-		dy = temp[1] - lat
+	tol := o.inversePrecision()
+	maxIter := o.maxIterations()
+
+	x, y := lon, lat
+	var rx, ry, a, b, c, d float64
+	newton := false
+	prev := math.Inf(1)
+	for i := 0; i < maxIter; i++ {
+		dLon, dLat := delta(x, y)
+		rx, ry = x+dLon-lon, y+dLat-lat
+		res := math.Max(math.Abs(rx), math.Abs(ry))
+		if res <= tol {
+			return x, y, nil
+		}
+
+		// 不动点迭代通常每步将残差缩小三个数量级以上，且无需计算雅可比矩阵；
+		// 收敛变慢时改用牛顿迭代，雅可比矩阵 J = I + ∂delta/∂p 仅在收敛再次变慢时重新计算
+		if res > prev*0.01 && (!newton || res > prev/2) {
+			a, b, c, d = deltaJacobian(x, y)
+			a++
+			d++
+			newton = true
+		}
+		prev = res
+
+		sx, sy := rx, ry
+		if newton {
+			// 求解 J·s = r，雅可比矩阵退化时退回不动点迭代
+			det := a*d - b*c
+			if nx, ny := (d*rx-b*ry)/det, (a*ry-c*rx)/det; math.Abs(det) > 0.5 && !math.IsNaN(nx) && !math.IsNaN(ny) {
+				sx, sy = nx, ny
+			}
+		}
+		x -= sx
+		y -= sy
 	}
-	return Position{wgsLon, wgsLat}
+	return x, y, &TransformError{
+		Type:    ErrTransformFailed,
+		Message: fmt.Sprintf("GCJ02 反解在 %d 次迭代内未收敛: [%v, %v]", maxIter, lon, lat),
+		Details: map[string]interface{}{
+			"position":   Position{lon, lat},
+			"iterations": maxIter,
+			"residual":   math.Max(math.Abs(rx), math.Abs(ry)),
+			"precision":  tol,
+		},
+	}
+}
+
+// deltaJacobian 返回 delta 的偏导数：
+// ∂dLon/∂lon, ∂dLon/∂lat, ∂dLat/∂lon, ∂dLat/∂lat
+func deltaJacobian(lon, lat float64) (float64, float64, float64, float64) {
+	x, y := lon-105, lat-35
+	const pi = math.Pi
+
+	// d√|x|/dx，x 为 0 时取 0
+	dSqrt := 0.0
+	if x != 0 {
+		dSqrt = math.Copysign(0.5/math.Sqrt(math.Abs(x)), x)
+	}
+	common := (120*pi*math.Cos(6*x*pi) + 40*pi*math.Cos(2*x*pi)) * 2 / 3
+
+	tLon := transformLon(x, y)
+	tLonX := 1 + 0.2*x + 0.1*y + 0.1*dSqrt + common
+	tLonX += (20*pi*math.Cos(x*pi) + 40*pi/3*math.Cos((x/3)*pi)) * 2 / 3
+	tLonX += (150*pi/12*math.Cos((x/12)*pi) + 300*pi/30*math.Cos((x/30)*pi)) * 2 / 3
+	tLonY := 2 + 0.1*x
+
+	tLat := transformLat(x, y)
+	tLatX := 2 + 0.1*y + 0.2*dSqrt + common
+	tLatY := 3 + 0.4*y + 0.1*x
+	tLatY += (20*pi*math.Cos(y*pi) + 40*pi/3*math.Cos((y/3)*pi)) * 2 / 3
+	tLatY += (160*pi/12*math.Cos((y/12)*pi) + 320*pi/30*math.Cos((y*pi)/30)) * 2 / 3
+
+	// dLon = tLon·kLon(φ)，dLat = tLat·kLat(φ)
+	radLat := lat * DegToRad
+	sin, cos := math.Sincos(radLat)
+	magic := 1 - GCJ02E2*sin*sin
+	sqrtMagic := math.Sqrt(magic)
+	kLon := 180 * sqrtMagic / (GCJ02A * cos * pi)
+	kLat := 180 * magic * sqrtMagic / (GCJ02A * (1 - GCJ02E2) * pi)
+
+	// 对纬度（度）求导
+	dMagic := -2 * GCJ02E2 * sin * cos * DegToRad
+	dKLon := 180 / (GCJ02A * pi) * (0.5*dMagic/(sqrtMagic*cos) + sqrtMagic*sin*DegToRad/(cos*cos))
+	dKLat := 180 / (GCJ02A * (1 - GCJ02E2) * pi) * 1.5 * sqrtMagic * dMagic
+
+	return tLonX * kLon, tLonY*kLon + tLon*dKLon, tLatX * kLat, tLatY*kLat + tLat*dKLat
 }
@@ -2,11 +2,42 @@ package gcoord
 
 import (
 	"encoding/json"
+	"fmt"
 	"math"
+	"sync"
+	"sync/atomic"
 )
 
-// transformAny 递归遍历 GeoJSON，原地转换 coordinates
-func transformAny(obj any, conv Converter) any {
+// geoJSONTransformer 在遍历 GeoJSON 时携带转换函数与选项
+type geoJSONTransformer struct {
+	step pointStep
+	opts TransformOptions
+	// to 目标坐标系，用于更新 crs 成员
+	to CRSTypes
+}
+
+// point 按选项转换一个点
+func (gt *geoJSONTransformer) point(x, y float64) (float64, float64, error) {
+	return gt.step(x, y, &gt.opts)
+}
+
+// document 转换完整的 GeoJSON 文档，错误的 Details 中 path 为出错坐标的 JSON 路径
+func (gt *geoJSONTransformer) document(obj any) (any, error) {
+	transform := gt.transform
+	if isCoordArray(obj) {
+		transform = gt.coords
+	}
+	out, err := transform(obj)
+	if err != nil {
+		return nil, atRoot(err)
+	}
+	gt.rootBBox(out)
+	return out, nil
+}
+
+// transform 递归遍历 GeoJSON，原地转换 coordinates 并更新 bbox、crs 成员，
+// 错误的 JSON 路径为相对 obj 的路径，见 withPath
+func (gt *geoJSONTransformer) transform(obj any) (any, error) {
 	switch t := obj.(type) {
 	case map[string]any:
 		// FeatureCollection / Feature / Geometry
@@ -14,20 +45,28 @@ func transformAny(obj any, conv Converter) any {
 			switch ty {
 			case "FeatureCollection":
 				if arr, ok := t["features"].([]any); ok {
-					for i := range arr {
-						arr[i] = transformAny(arr[i], conv)
+					if err := gt.transformFeatures(arr); err != nil {
+						return nil, err
 					}
 					t["features"] = arr
 				}
 			case "Feature":
 				if g, ok := t["geometry"].(map[string]any); ok {
-					t["geometry"] = transformAny(g, conv)
+					out, err := gt.transform(g)
+					if err != nil {
+						return nil, withPath(err, ".geometry")
+					}
+					t["geometry"] = out
 				}
 			case "GeometryCollection":
 				if geoms, ok := t["geometries"].([]any); ok {
 					for i := range geoms {
 						if gm, ok := geoms[i].(map[string]any); ok {
-							geoms[i] = transformAny(gm, conv)
+							out, err := gt.transform(gm)
+							if err != nil {
+								return nil, withPath(err, fmt.Sprintf(".geometries[%d]", i))
+							}
+							geoms[i] = out
 						}
 					}
 					t["geometries"] = geoms
@@ -35,47 +74,160 @@ func transformAny(obj any, conv Converter) any {
 			default:
 				// Geometry
 				if coords, ok := t["coordinates"]; ok {
-					t["coordinates"] = transformCoords(coords, conv)
+					out, err := gt.coords(coords)
+					if err != nil {
+						return nil, withPath(err, ".coordinates")
+					}
+					t["coordinates"] = out
 				}
 			}
+			gt.updateMembers(t)
 		}
-		return t
+		return t, nil
 	case []any:
 		for i := range t {
-			t[i] = transformAny(t[i], conv)
+			out, err := gt.transform(t[i])
+			if err != nil {
+				return nil, withPath(err, fmt.Sprintf("[%d]", i))
+			}
+			t[i] = out
 		}
-		return t
+		return t, nil
 	default:
-		return obj
+		return obj, nil
+	}
+}
+
+// transformFeatures 原地转换 features，Workers>1 时并行转换
+func (gt *geoJSONTransformer) transformFeatures(features []any) error {
+	return gt.eachFeature(len(features), func(i int) error {
+		out, err := gt.transformFeature(i, features[i])
+		if err == nil {
+			features[i] = out
+		}
+		return err
+	})
+}
+
+// eachFeature 对序号 0..n-1 的 feature 调用 fn，Workers>1 时并行调用。
+// 出错时停止，返回序号最小的错误，错误路径前加上 ".features[i]"
+func (gt *geoJSONTransformer) eachFeature(n int, fn func(i int) error) error {
+	workers := min(gt.opts.Workers, n)
+	if workers <= 1 {
+		for i := 0; i < n; i++ {
+			if err := fn(i); err != nil {
+				return withPath(err, fmt.Sprintf(".features[%d]", i))
+			}
+		}
+		return nil
+	}
+
+	var (
+		wg       sync.WaitGroup
+		next     atomic.Int64
+		failed   atomic.Bool
+		errMutex sync.Mutex
+		errIndex = n
+		firstErr error
+	)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for !failed.Load() {
+				i := int(next.Add(1) - 1)
+				if i >= n {
+					return
+				}
+				if err := fn(i); err != nil {
+					errMutex.Lock()
+					// 多个 feature 出错时保留序号最小的错误
+					if i < errIndex {
+						errIndex, firstErr = i, err
+					}
+					errMutex.Unlock()
+					failed.Store(true)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if firstErr != nil {
+		return withPath(firstErr, fmt.Sprintf(".features[%d]", errIndex))
+	}
+	return nil
+}
+
+// transformFeature 转换单个 feature，并将转换函数中的 panic 转为错误
+func (gt *geoJSONTransformer) transformFeature(i int, feature any) (out any, err error) {
+	defer recoverFeature(i, &err)
+	return gt.transform(feature)
+}
+
+// recoverFeature 将转换第 i 个 feature 时的 panic 转为错误，需通过 defer 调用
+func recoverFeature(i int, err *error) {
+	if r := recover(); r != nil {
+		*err = &TransformError{
+			Type:    ErrTransformFailed,
+			Message: fmt.Sprintf("转换 features[%d] 失败: %v", i, r),
+			Details: map[string]interface{}{
+				"index": i,
+				"panic": r,
+			},
+		}
 	}
 }
 
-// transformCoords 转换坐标数组
-func transformCoords(coords any, conv Converter) any {
+// coords 转换坐标数组
+func (gt *geoJSONTransformer) coords(coords any) (any, error) {
 	switch c := coords.(type) {
 	case []any:
 		// 可能是 [x,y] 或 多维数组
 		if len(c) >= 2 {
-			// 判断是否为数值型坐标（允许超过2维，保留额外维度）
+			// 判断是否为数值型坐标（允许超过2维，默认保留额外维度）
 			x := toFloat(c[0])
 			y := toFloat(c[1])
 			if !math.IsNaN(x) && !math.IsNaN(y) && (len(c) == 2 || isAllNumbers(c)) {
-				res := conv(Position{x, y})
-				out := make([]any, len(c))
-				out[0] = res[0]
-				out[1] = res[1]
-				if len(c) > 2 {
-					copy(out[2:], c[2:])
+				rx, ry, err := gt.point(x, y)
+				if err != nil {
+					return nil, err
+				}
+				if gt.opts.DropExtraDims {
+					return []any{rx, ry}, nil
 				}
-				return out
+				out := make([]any, len(c))
+				out[0] = rx
+				out[1] = ry
+				copy(out[2:], c[2:])
+				return out, nil
 			}
 		}
 		for i := range c {
-			c[i] = transformCoords(c[i], conv)
+			out, err := gt.coords(c[i])
+			if err != nil {
+				return nil, withPath(err, fmt.Sprintf("[%d]", i))
+			}
+			c[i] = out
 		}
-		return c
+		return c, nil
 	default:
-		return coords
+		return coords, nil
+	}
+}
+
+// isCoordArray 判断值是否为坐标数组，如 [x, y] 或 [[x, y], ...]，按第一个叶子元素是否为数字判断
+func isCoordArray(v any) bool {
+	for {
+		arr, ok := v.([]any)
+		if !ok || len(arr) == 0 {
+			return false
+		}
+		switch arr[0].(type) {
+		case float64, float32, int, int64, json.Number:
+			return true
+		}
+		v = arr[0]
 	}
 }
 
@@ -0,0 +1,526 @@
+package gcoord
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// Geometry 类型化的 GeoJSON 几何对象，由 *Point、*LineString、*Polygon、*MultiPoint、
+// *MultiLineString、*MultiPolygon、*GeometryCollection 实现
+type Geometry interface {
+	// GeoJSONType 返回 GeoJSON 的 type 成员，如 "Point"
+	GeoJSONType() string
+
+	// eachPosition 遍历所有坐标，fn 出错时停止，错误路径为相对几何对象的路径
+	eachPosition(fn func(p *Position) error) error
+	bboxRef() *[]float64
+}
+
+// Point GeoJSON Point
+type Point struct {
+	Coordinates Position
+	BBox        []float64
+}
+
+// LineString GeoJSON LineString
+type LineString struct {
+	Coordinates []Position
+	BBox        []float64
+}
+
+// Polygon GeoJSON Polygon，Coordinates 为线性环，第一个为外环
+type Polygon struct {
+	Coordinates [][]Position
+	BBox        []float64
+}
+
+// MultiPoint GeoJSON MultiPoint
+type MultiPoint struct {
+	Coordinates []Position
+	BBox        []float64
+}
+
+// MultiLineString GeoJSON MultiLineString
+type MultiLineString struct {
+	Coordinates [][]Position
+	BBox        []float64
+}
+
+// MultiPolygon GeoJSON MultiPolygon
+type MultiPolygon struct {
+	Coordinates [][][]Position
+	BBox        []float64
+}
+
+// GeometryCollection GeoJSON GeometryCollection
+type GeometryCollection struct {
+	Geometries []Geometry
+	BBox       []float64
+}
+
+// Feature GeoJSON Feature，Geometry 为 nil 时输出 null。
+// CRS 为 GeoJSON 2008 的 crs 成员，转换时更新为目标坐标系
+type Feature struct {
+	ID         any
+	Geometry   Geometry
+	Properties map[string]any
+	BBox       []float64
+	CRS        map[string]any
+}
+
+// FeatureCollection GeoJSON FeatureCollection
+type FeatureCollection struct {
+	Features []*Feature
+	BBox     []float64
+	CRS      map[string]any
+}
+
+func (*Point) GeoJSONType() string              { return "Point" }
+func (*LineString) GeoJSONType() string         { return "LineString" }
+func (*Polygon) GeoJSONType() string            { return "Polygon" }
+func (*MultiPoint) GeoJSONType() string         { return "MultiPoint" }
+func (*MultiLineString) GeoJSONType() string    { return "MultiLineString" }
+func (*MultiPolygon) GeoJSONType() string       { return "MultiPolygon" }
+func (*GeometryCollection) GeoJSONType() string { return "GeometryCollection" }
+
+func (g *Point) bboxRef() *[]float64              { return &g.BBox }
+func (g *LineString) bboxRef() *[]float64         { return &g.BBox }
+func (g *Polygon) bboxRef() *[]float64            { return &g.BBox }
+func (g *MultiPoint) bboxRef() *[]float64         { return &g.BBox }
+func (g *MultiLineString) bboxRef() *[]float64    { return &g.BBox }
+func (g *MultiPolygon) bboxRef() *[]float64       { return &g.BBox }
+func (g *GeometryCollection) bboxRef() *[]float64 { return &g.BBox }
+
+func (g *Point) eachPosition(fn func(p *Position) error) error {
+	return withPath(fn(&g.Coordinates), ".coordinates")
+}
+
+func (g *LineString) eachPosition(fn func(p *Position) error) error {
+	return withPath(eachPosition1(g.Coordinates, fn), ".coordinates")
+}
+
+func (g *Polygon) eachPosition(fn func(p *Position) error) error {
+	return withPath(eachPosition2(g.Coordinates, fn), ".coordinates")
+}
+
+func (g *MultiPoint) eachPosition(fn func(p *Position) error) error {
+	return withPath(eachPosition1(g.Coordinates, fn), ".coordinates")
+}
+
+func (g *MultiLineString) eachPosition(fn func(p *Position) error) error {
+	return withPath(eachPosition2(g.Coordinates, fn), ".coordinates")
+}
+
+func (g *MultiPolygon) eachPosition(fn func(p *Position) error) error {
+	for i := range g.Coordinates {
+		if err := eachPosition2(g.Coordinates[i], fn); err != nil {
+			return withPath(err, fmt.Sprintf(".coordinates[%d]", i))
+		}
+	}
+	return nil
+}
+
+func (g *GeometryCollection) eachPosition(fn func(p *Position) error) error {
+	for i, member := range g.Geometries {
+		if member == nil {
+			continue
+		}
+		if err := member.eachPosition(fn); err != nil {
+			return withPath(err, fmt.Sprintf(".geometries[%d]", i))
+		}
+	}
+	return nil
+}
+
+func eachPosition1(ps []Position, fn func(p *Position) error) error {
+	for i := range ps {
+		if err := fn(&ps[i]); err != nil {
+			return withPath(err, fmt.Sprintf("[%d]", i))
+		}
+	}
+	return nil
+}
+
+func eachPosition2(pss [][]Position, fn func(p *Position) error) error {
+	for i := range pss {
+		if err := eachPosition1(pss[i], fn); err != nil {
+			return withPath(err, fmt.Sprintf("[%d]", i))
+		}
+	}
+	return nil
+}
+
+// geometryMember 几何对象的 JSON 表示
+type geometryMember[C any] struct {
+	Type        string    `json:"type"`
+	BBox        []float64 `json:"bbox,omitempty"`
+	Coordinates C         `json:"coordinates"`
+}
+
+func marshalGeometry[C any](typ string, coords C, bbox []float64) ([]byte, error) {
+	return json.Marshal(geometryMember[C]{typ, bbox, coords})
+}
+
+// unmarshalGeometry 解析几何对象并检查 type 成员
+func unmarshalGeometry[C any](data []byte, typ string, coords *C, bbox *[]float64) error {
+	var m geometryMember[C]
+	if err := json.Unmarshal(data, &m); err != nil {
+		return ErrJSONParseFailed(err)
+	}
+	if m.Type != typ {
+		return errGeoJSONType(typ, m.Type)
+	}
+	*coords, *bbox = m.Coordinates, m.BBox
+	return nil
+}
+
+func errGeoJSONType(want, got string) *TransformError {
+	return &TransformError{
+		Type:    ErrInvalidInput,
+		Message: fmt.Sprintf("GeoJSON 类型不匹配: 期望 %s，得到 %q", want, got),
+		Details: map[string]interface{}{
+			"expected": want,
+			"type":     got,
+		},
+	}
+}
+
+func (g Point) MarshalJSON() ([]byte, error) {
+	return marshalGeometry("Point", g.Coordinates, g.BBox)
+}
+
+func (g LineString) MarshalJSON() ([]byte, error) {
+	return marshalGeometry("LineString", g.Coordinates, g.BBox)
+}
+
+func (g Polygon) MarshalJSON() ([]byte, error) {
+	return marshalGeometry("Polygon", g.Coordinates, g.BBox)
+}
+
+func (g MultiPoint) MarshalJSON() ([]byte, error) {
+	return marshalGeometry("MultiPoint", g.Coordinates, g.BBox)
+}
+
+func (g MultiLineString) MarshalJSON() ([]byte, error) {
+	return marshalGeometry("MultiLineString", g.Coordinates, g.BBox)
+}
+
+func (g MultiPolygon) MarshalJSON() ([]byte, error) {
+	return marshalGeometry("MultiPolygon", g.Coordinates, g.BBox)
+}
+
+func (g *Point) UnmarshalJSON(data []byte) error {
+	return unmarshalGeometry(data, "Point", &g.Coordinates, &g.BBox)
+}
+
+func (g *LineString) UnmarshalJSON(data []byte) error {
+	return unmarshalGeometry(data, "LineString", &g.Coordinates, &g.BBox)
+}
+
+func (g *Polygon) UnmarshalJSON(data []byte) error {
+	return unmarshalGeometry(data, "Polygon", &g.Coordinates, &g.BBox)
+}
+
+func (g *MultiPoint) UnmarshalJSON(data []byte) error {
+	return unmarshalGeometry(data, "MultiPoint", &g.Coordinates, &g.BBox)
+}
+
+func (g *MultiLineString) UnmarshalJSON(data []byte) error {
+	return unmarshalGeometry(data, "MultiLineString", &g.Coordinates, &g.BBox)
+}
+
+func (g *MultiPolygon) UnmarshalJSON(data []byte) error {
+	return unmarshalGeometry(data, "MultiPolygon", &g.Coordinates, &g.BBox)
+}
+
+func (g GeometryCollection) MarshalJSON() ([]byte, error) {
+	geoms := g.Geometries
+	if geoms == nil {
+		geoms = []Geometry{}
+	}
+	return json.Marshal(struct {
+		Type       string     `json:"type"`
+		BBox       []float64  `json:"bbox,omitempty"`
+		Geometries []Geometry `json:"geometries"`
+	}{"GeometryCollection", g.BBox, geoms})
+}
+
+func (g *GeometryCollection) UnmarshalJSON(data []byte) error {
+	var m struct {
+		Type       string            `json:"type"`
+		BBox       []float64         `json:"bbox"`
+		Geometries []json.RawMessage `json:"geometries"`
+	}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return ErrJSONParseFailed(err)
+	}
+	if m.Type != "GeometryCollection" {
+		return errGeoJSONType("GeometryCollection", m.Type)
+	}
+	g.BBox = m.BBox
+	g.Geometries = make([]Geometry, len(m.Geometries))
+	for i, raw := range m.Geometries {
+		member, err := UnmarshalGeometry(raw)
+		if err != nil {
+			return err
+		}
+		g.Geometries[i] = member
+	}
+	return nil
+}
+
+// UnmarshalGeometry 按 type 成员将 JSON 解析为对应的几何类型，null 返回 nil
+func UnmarshalGeometry(data []byte) (Geometry, error) {
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		return nil, nil
+	}
+	var head struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(data, &head); err != nil {
+		return nil, ErrJSONParseFailed(err)
+	}
+	var g Geometry
+	switch head.Type {
+	case "Point":
+		g = &Point{}
+	case "LineString":
+		g = &LineString{}
+	case "Polygon":
+		g = &Polygon{}
+	case "MultiPoint":
+		g = &MultiPoint{}
+	case "MultiLineString":
+		g = &MultiLineString{}
+	case "MultiPolygon":
+		g = &MultiPolygon{}
+	case "GeometryCollection":
+		g = &GeometryCollection{}
+	default:
+		return nil, &TransformError{
+			Type:    ErrUnsupportedFormat,
+			Message: fmt.Sprintf("不支持的 GeoJSON 几何类型: %q", head.Type),
+			Details: map[string]interface{}{
+				"type": head.Type,
+			},
+		}
+	}
+	if err := json.Unmarshal(data, g); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+// featureMember Feature 的 JSON 表示
+type featureMember struct {
+	Type       string          `json:"type"`
+	ID         any             `json:"id,omitempty"`
+	BBox       []float64       `json:"bbox,omitempty"`
+	CRS        map[string]any  `json:"crs,omitempty"`
+	Geometry   json.RawMessage `json:"geometry"`
+	Properties map[string]any  `json:"properties"`
+}
+
+func (f Feature) MarshalJSON() ([]byte, error) {
+	geometry := json.RawMessage("null")
+	if f.Geometry != nil {
+		b, err := json.Marshal(f.Geometry)
+		if err != nil {
+			return nil, err
+		}
+		geometry = b
+	}
+	return json.Marshal(featureMember{"Feature", f.ID, f.BBox, f.CRS, geometry, f.Properties})
+}
+
+func (f *Feature) UnmarshalJSON(data []byte) error {
+	var m featureMember
+	if err := json.Unmarshal(data, &m); err != nil {
+		return ErrJSONParseFailed(err)
+	}
+	if m.Type != "Feature" {
+		return errGeoJSONType("Feature", m.Type)
+	}
+	var g Geometry
+	if len(m.Geometry) > 0 {
+		var err error
+		if g, err = UnmarshalGeometry(m.Geometry); err != nil {
+			return err
+		}
+	}
+	*f = Feature{ID: m.ID, Geometry: g, Properties: m.Properties, BBox: m.BBox, CRS: m.CRS}
+	return nil
+}
+
+// featureCollectionMember FeatureCollection 的 JSON 表示
+type featureCollectionMember struct {
+	Type     string         `json:"type"`
+	BBox     []float64      `json:"bbox,omitempty"`
+	CRS      map[string]any `json:"crs,omitempty"`
+	Features []*Feature     `json:"features"`
+}
+
+func (fc FeatureCollection) MarshalJSON() ([]byte, error) {
+	features := fc.Features
+	if features == nil {
+		features = []*Feature{}
+	}
+	return json.Marshal(featureCollectionMember{"FeatureCollection", fc.BBox, fc.CRS, features})
+}
+
+func (fc *FeatureCollection) UnmarshalJSON(data []byte) error {
+	var m featureCollectionMember
+	if err := json.Unmarshal(data, &m); err != nil {
+		if te, ok := err.(*TransformError); ok {
+			return te
+		}
+		return ErrJSONParseFailed(err)
+	}
+	if m.Type != "FeatureCollection" {
+		return errGeoJSONType("FeatureCollection", m.Type)
+	}
+	*fc = FeatureCollection{Features: m.Features, BBox: m.BBox, CRS: m.CRS}
+	return nil
+}
+
+// transformTyped 原地转换类型化 GeoJSON 对象。p 指向 Transform 的输入，
+// 输入可以是值或指针，如 FeatureCollection、*Feature、Point、Geometry；
+// 不是类型化 GeoJSON 对象时返回 false
+func transformTyped[T any](p *T, gt *geoJSONTransformer) (bool, error) {
+	obj := any(p)
+	switch obj.(type) {
+	case *FeatureCollection, *Feature, Geometry:
+	default:
+		obj = any(*p)
+	}
+
+	var err error
+	switch v := obj.(type) {
+	case *FeatureCollection:
+		err = gt.featureCollection(v)
+	case *Feature:
+		err = gt.feature(v)
+	case Geometry:
+		if err = gt.geometry(v); err == nil && gt.opts.AddBBox {
+			setBBox(v.bboxRef(), v, true)
+		}
+	default:
+		return false, nil
+	}
+	return true, atRoot(err)
+}
+
+func (gt *geoJSONTransformer) featureCollection(fc *FeatureCollection) error {
+	if fc == nil {
+		return nil
+	}
+	err := gt.eachFeature(len(fc.Features), func(i int) (err error) {
+		defer recoverFeature(i, &err)
+		return gt.feature(fc.Features[i])
+	})
+	if err != nil {
+		return err
+	}
+	if fc.BBox != nil || gt.opts.AddBBox {
+		var b bounds
+		for _, f := range fc.Features {
+			if f != nil && f.Geometry != nil {
+				b.geometry(f.Geometry)
+			}
+		}
+		b.setBBox(&fc.BBox, true)
+	}
+	if fc.CRS != nil {
+		fc.CRS = crsMember(gt.to)
+	}
+	return nil
+}
+
+func (gt *geoJSONTransformer) feature(f *Feature) error {
+	if f == nil {
+		return nil
+	}
+	if f.Geometry != nil {
+		if err := gt.geometry(f.Geometry); err != nil {
+			return withPath(err, ".geometry")
+		}
+		if f.BBox != nil || gt.opts.AddBBox {
+			setBBox(&f.BBox, f.Geometry, true)
+		}
+	}
+	if f.CRS != nil {
+		f.CRS = crsMember(gt.to)
+	}
+	return nil
+}
+
+// geometry 原地转换几何对象的坐标，并重新计算已有的 bbox
+func (gt *geoJSONTransformer) geometry(g Geometry) error {
+	if gc, ok := g.(*GeometryCollection); ok {
+		for i, member := range gc.Geometries {
+			if member == nil {
+				continue
+			}
+			if err := gt.geometry(member); err != nil {
+				return withPath(err, fmt.Sprintf(".geometries[%d]", i))
+			}
+		}
+	} else if err := g.eachPosition(gt.position); err != nil {
+		return err
+	}
+	setBBox(g.bboxRef(), g, false)
+	return nil
+}
+
+// position 原地转换一个坐标，长度不足 2 时返回 ErrInvalidPosition
+func (gt *geoJSONTransformer) position(p *Position) error {
+	if len(*p) < 2 {
+		return ErrInvalidPosition
+	}
+	x, y, err := gt.point((*p)[0], (*p)[1])
+	if err != nil {
+		return err
+	}
+	(*p)[0], (*p)[1] = x, y
+	if gt.opts.DropExtraDims {
+		*p = (*p)[:2]
+	}
+	return nil
+}
+
+// geometry 将几何对象的所有坐标加入外包矩形
+func (b *bounds) geometry(g Geometry) {
+	g.eachPosition(func(p *Position) error {
+		if len(*p) >= 2 {
+			b.add((*p)[0], (*p)[1])
+		}
+		return nil
+	})
+}
+
+// setBBox 按几何对象的坐标更新 bbox，规则与 bounds.apply 相同，add 为 false 时不新建 bbox
+func setBBox(bbox *[]float64, g Geometry, add bool) {
+	if *bbox == nil && !add {
+		return
+	}
+	var b bounds
+	b.geometry(g)
+	b.setBBox(bbox, add)
+}
+
+func (b *bounds) setBBox(bbox *[]float64, add bool) {
+	if !b.ok {
+		return
+	}
+	switch old := *bbox; len(old) {
+	case 0:
+		if old == nil && add {
+			*bbox = []float64{b.minX, b.minY, b.maxX, b.maxY}
+		}
+	case 4:
+		*bbox = []float64{b.minX, b.minY, b.maxX, b.maxY}
+	case 6:
+		*bbox = []float64{b.minX, b.minY, old[2], b.maxX, b.maxY, old[5]}
+	}
+}
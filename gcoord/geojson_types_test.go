@@ -0,0 +1,105 @@
+package gcoord
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+const typedFC = `{"type":"FeatureCollection","features":[
+	{"type":"Feature","id":1,"properties":{"name":"天安门"},"geometry":{"type":"Point","coordinates":[116.397,39.908,43.5]}},
+	{"type":"Feature","properties":null,"bbox":[116.3,39.8,116.4,39.9],"geometry":{"type":"LineString","coordinates":[[116.3,39.8],[116.4,39.9]]}},
+	{"type":"Feature","properties":{},"geometry":{"type":"Polygon","coordinates":[[[116.3,39.8],[116.4,39.8],[116.4,39.9],[116.3,39.8]]]}},
+	{"type":"Feature","properties":{},"geometry":{"type":"MultiPolygon","coordinates":[[[[116.3,39.8],[116.4,39.8],[116.4,39.9],[116.3,39.8]]]]}},
+	{"type":"Feature","properties":{},"geometry":{"type":"GeometryCollection","geometries":[
+		{"type":"MultiPoint","coordinates":[[116.3,39.8]]},
+		{"type":"MultiLineString","coordinates":[[[116.3,39.8],[116.4,39.9]]]}]}},
+	{"type":"Feature","properties":{},"geometry":null}
+]}`
+
+func TestTypedGeoJSONRoundTrip(t *testing.T) {
+	var fc FeatureCollection
+	if err := json.Unmarshal([]byte(typedFC), &fc); err != nil {
+		t.Fatal(err)
+	}
+	if len(fc.Features) != 6 || fc.Features[5].Geometry != nil {
+		t.Fatalf("unexpected features: %+v", fc.Features)
+	}
+	if _, ok := fc.Features[4].Geometry.(*GeometryCollection).Geometries[1].(*MultiLineString); !ok {
+		t.Fatal("GeometryCollection member type lost")
+	}
+
+	b, err := json.Marshal(fc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got, want any
+	json.Unmarshal(b, &got)
+	json.Unmarshal([]byte(typedFC), &want)
+	gb, _ := json.Marshal(got)
+	wb, _ := json.Marshal(want)
+	if string(gb) != string(wb) {
+		t.Fatalf("round trip mismatch:\n%s\n%s", gb, wb)
+	}
+
+	var p Point
+	if err := json.Unmarshal([]byte(`{"type":"LineString","coordinates":[]}`), &p); GetErrorType(err) != ErrInvalidInput {
+		t.Fatalf("expected type mismatch error, got %v", err)
+	}
+	if _, err := UnmarshalGeometry([]byte(`{"type":"Circle"}`)); GetErrorType(err) != ErrUnsupportedFormat {
+		t.Fatalf("expected ErrUnsupportedFormat, got %v", err)
+	}
+}
+
+func TestTransformTypedGeoJSON(t *testing.T) {
+	var fc FeatureCollection
+	if err := json.Unmarshal([]byte(typedFC), &fc); err != nil {
+		t.Fatal(err)
+	}
+	// 与 map 形式的结果一致
+	wantJSON, err := Transform(typedFC, WGS84, GCJ02)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := TransformWithOptions(fc, WGS84, GCJ02, TransformOptions{Workers: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got, want any
+	b, _ := json.Marshal(out)
+	json.Unmarshal(b, &got)
+	json.Unmarshal([]byte(wantJSON), &want)
+	gb, _ := json.Marshal(got)
+	wb, _ := json.Marshal(want)
+	if string(gb) != string(wb) {
+		t.Fatalf("typed result differs from map result:\n%s\n%s", gb, wb)
+	}
+	if z := out.Features[0].Geometry.(*Point).Coordinates[2]; z != 43.5 {
+		t.Fatalf("Z not preserved: %v", z)
+	}
+
+	// 指针与 Geometry 接口
+	ptr := &Feature{Geometry: &Point{Coordinates: Position{116.397, 39.908}}}
+	if res, err := Transform(ptr, WGS84, BD09); err != nil || res != ptr || res.Geometry.(*Point).Coordinates[0] == 116.397 {
+		t.Fatalf("pointer input: %+v (%v)", res, err)
+	}
+	var g Geometry = &LineString{Coordinates: []Position{{116.3, 39.8}, {116.4, 39.9}}}
+	g, err = TransformWithOptions(g, WGS84, EPSG3857, TransformOptions{AddBBox: true})
+	if ls := g.(*LineString); err != nil || len(ls.BBox) != 4 || ls.BBox[0] != ls.Coordinates[0][0] {
+		t.Fatalf("Geometry input: %+v (%v)", g, err)
+	}
+	pt, err := Transform(Point{Coordinates: Position{116.397, 39.908}}, WGS84, GCJ02)
+	if err != nil || pt.Coordinates[0] == 116.397 {
+		t.Fatalf("value input: %+v (%v)", pt, err)
+	}
+
+	// 错误路径
+	bad := FeatureCollection{Features: []*Feature{
+		{Geometry: &Point{Coordinates: Position{116.397, 39.908}}},
+		{Geometry: &Polygon{Coordinates: [][]Position{{{116.3, 39.8}, {116.4, 95}}}}},
+	}}
+	_, err = TransformWithOptions(bad, WGS84, GCJ02, TransformOptions{Strict: true})
+	if te, ok := err.(*TransformError); !ok || te.Details["path"] != "$.features[1].geometry.coordinates[0][1]" {
+		t.Fatalf("unexpected error %#v", err)
+	}
+}
@@ -40,7 +40,8 @@ type TransformValidator interface {
 type baseConverter struct {
 	sourceCRS CRSTypes
 	targetCRS CRSTypes
-	converter Converter
+	step      pointStep
+	opts      TransformOptions
 	precision float64
 }
 
@@ -48,7 +49,11 @@ func (c *baseConverter) Convert(coord Position) (Position, error) {
 	if err := validatePosition(coord); err != nil {
 		return nil, err
 	}
-	return c.converter(coord), nil
+	x, y, err := c.step(coord[0], coord[1], &c.opts)
+	if err != nil {
+		return nil, err
+	}
+	return withXY(coord, x, y), nil
 }
 
 func (c *baseConverter) GetSourceCRS() CRSTypes {
@@ -65,20 +70,35 @@ func (c *baseConverter) GetPrecision() float64 {
 
 // NewConverter 创建新的转换器
 func NewConverter(from, to CRSTypes) (CoordinateConverter, error) {
-	converter := getConverter(from, to)
-	if converter == nil {
-		return nil, ErrUnsupportedCRS(to)
+	return NewConverterWithOptions(from, to, TransformOptions{})
+}
+
+// NewConverterWithOptions 创建按 opts 转换的转换器，例如精确反解 BD09：
+//
+//	conv, err := NewConverterWithOptions(BD09, GCJ02, TransformOptions{ExactBD09: true})
+func NewConverterWithOptions(from, to CRSTypes, opts TransformOptions) (CoordinateConverter, error) {
+	from, err := validateCRS(from)
+	if err != nil {
+		return nil, err
+	}
+	to, err = validateCRS(to)
+	if err != nil {
+		return nil, err
 	}
 
-	precision := LonLatPrecision
-	if to == EPSG3857 || to == BD09MC {
-		precision = ProjectionPrecision
+	step := resolveStep(from, to, &opts)
+	if step == nil {
+		return nil, ErrNoConverter(from, to)
 	}
 
+	info, _ := LookupCRS(to)
+	precision := info.Precision
+
 	return &baseConverter{
 		sourceCRS: from,
 		targetCRS: to,
-		converter: converter,
+		step:      step,
+		opts:      opts,
 		precision: precision,
 	}, nil
 }
@@ -0,0 +1,134 @@
+package gcoord
+
+import (
+	"fmt"
+	"math"
+)
+
+// OutOfChinaPolicy WGS84↔GCJ02 转换时中国境外坐标的处理方式
+type OutOfChinaPolicy uint8
+
+const (
+	// OutOfChinaPass 境外坐标原样返回（默认）
+	OutOfChinaPass OutOfChinaPolicy = iota
+	// OutOfChinaError 境外坐标返回 ErrInvalidInput 错误
+	OutOfChinaError
+)
+
+// BoundsPolicy WGS84→EPSG3857 结果超出 Web 墨卡托范围时的处理方式
+type BoundsPolicy uint8
+
+const (
+	// BoundsClamp 限制在 ±MaxExtent 内（默认）
+	BoundsClamp BoundsPolicy = iota
+	// BoundsError 返回 ErrInvalidInput 错误
+	BoundsError
+)
+
+// TransformOptions 控制 TransformWithOptions 的转换行为，零值与 Transform 的默认行为一致
+type TransformOptions struct {
+	// Workers 并行转换 FeatureCollection 中 features 时使用的 goroutine 数，
+	// <=1 时顺序转换。并行转换保持 features 的顺序，出错时返回序号最小的错误
+	Workers int
+
+	// PolylinePrecision 转换 Polyline 时的坐标小数位数，为 0 时使用 DefaultPolylinePrecision
+	PolylinePrecision int
+
+	// InversePrecision 迭代反解（如 GCJ02→WGS84）的收敛精度，单位为度，
+	// 为 0 时使用 IterationPrecision，精确模式下使用 ExactInversePrecision
+	InversePrecision float64
+	// MaxIterations 迭代反解的最大迭代次数，为 0 时使用 DefaultMaxIterations，
+	// 超过时返回 ErrTransformFailed 错误
+	MaxIterations int
+
+	// ExactBD09 为 true 时 BD09→GCJ02 迭代反解 GCJ02→BD09，使往返误差小于 1 毫米；
+	// 默认使用近似反算公式，往返误差可达约 20 厘米
+	ExactBD09 bool
+
+	// ExcludeRegions WGS84↔GCJ02 转换时不加偏的区域，如 RegionHongKong|RegionMacau|RegionTaiwan，
+	// 默认港澳台与大陆一样加偏
+	ExcludeRegions OffsetRegion
+	// OutOfChina WGS84↔GCJ02 转换时中国境外坐标的处理方式，默认原样返回。
+	// ExcludeRegions 中的区域不视为境外
+	OutOfChina OutOfChinaPolicy
+
+	// Strict 为 true 时拒绝 NaN、Inf 以及超出源坐标系有效范围（见 CRSInfo.Extent）的坐标，
+	// 返回 ErrInvalidInput 错误，Details 中 value 为无效的值，GeoJSON 输入时 path 为坐标的 JSON 路径
+	Strict bool
+	// Bounds WGS84→EPSG3857 超出 Web 墨卡托范围（纬度约 ±85.05°）时的处理方式，默认限制在范围内
+	Bounds BoundsPolicy
+	// DropExtraDims 为 true 时丢弃 Z、M 等额外维度，只输出 [x, y]；默认保留
+	DropExtraDims bool
+
+	// AddBBox 为 true 时为根对象和每个 Feature 补充 bbox；
+	// 无论是否设置，已有的 bbox 都会按转换后的坐标重新计算
+	AddBBox bool
+
+	// UpdateCRSField 为 true 时 TransformStruct 将已转换记录的 crs 字段更新为目标坐标系，
+	// 重复调用不会再次转换；默认不修改 crs 字段
+	UpdateCRSField bool
+}
+
+func (o *TransformOptions) inversePrecision() float64 {
+	if o.InversePrecision > 0 {
+		return o.InversePrecision
+	}
+	return IterationPrecision
+}
+
+func (o *TransformOptions) maxIterations() int {
+	if o.MaxIterations > 0 {
+		return o.MaxIterations
+	}
+	return DefaultMaxIterations
+}
+
+// resolveStep 获取 from 到 to 的转换步骤，Strict 时在转换前按 from 的有效范围校验源坐标，
+// 没有转换路径时返回 nil
+func resolveStep(from, to CRSTypes, opts *TransformOptions) pointStep {
+	step := getStep(from, to)
+	if step == nil || !opts.Strict {
+		return step
+	}
+	info, _ := LookupCRS(from)
+	return func(x, y float64, o *TransformOptions) (float64, float64, error) {
+		if err := checkCoord(x, y, info); err != nil {
+			return x, y, err
+		}
+		return step(x, y, o)
+	}
+}
+
+// checkCoord 校验坐标为有限值且在坐标系的有效范围内，
+// 错误的 Details 中 value 为第一个无效的分量
+func checkCoord(x, y float64, info CRSInfo) error {
+	e := info.Extent
+	for _, v := range [2]float64{x, y} {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return errInvalidCoord(x, y, v, info, "不是有限数值")
+		}
+	}
+	if x < e[0] || x > e[2] {
+		return errInvalidCoord(x, y, x, info, fmt.Sprintf("x 超出范围 [%v, %v]", e[0], e[2]))
+	}
+	if y < e[1] || y > e[3] {
+		return errInvalidCoord(x, y, y, info, fmt.Sprintf("y 超出范围 [%v, %v]", e[1], e[3]))
+	}
+	return nil
+}
+
+func errInvalidCoord(x, y, value float64, info CRSInfo, reason string) *TransformError {
+	unit := "度"
+	if info.Projected {
+		unit = "米"
+	}
+	return &TransformError{
+		Type:    ErrInvalidInput,
+		Message: fmt.Sprintf("%s 坐标 [%v, %v] 无效: %s（单位: %s）", info.Name, x, y, reason, unit),
+		Details: map[string]interface{}{
+			"position": Position{x, y},
+			"value":    value,
+			"crs":      info.Name,
+		},
+	}
+}
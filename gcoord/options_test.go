@@ -0,0 +1,185 @@
+package gcoord
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestOutOfChinaPolicy(t *testing.T) {
+	seoul := Position{126.98, 37.57}
+	strict := TransformOptions{OutOfChina: OutOfChinaError}
+
+	for _, to := range []CRSTypes{GCJ02, BD09, BD09MC} {
+		if _, err := TransformWithOptions(seoul, WGS84, to, strict); GetErrorType(err) != ErrInvalidInput {
+			t.Fatalf("WGS84→%s: expected ErrInvalidInput, got %v", to, err)
+		}
+	}
+	if _, err := TransformWithOptions(seoul, GCJ02, WGS84, strict); GetErrorType(err) != ErrInvalidInput {
+		t.Fatalf("GCJ02→WGS84: expected ErrInvalidInput, got %v", err)
+	}
+	if got, err := Transform(seoul, WGS84, GCJ02); err != nil || !approxPos(got, seoul, 0) {
+		t.Fatalf("default should pass through: %v (%v)", got, err)
+	}
+	if _, err := TransformWithOptions(Position{116.40, 39.90}, WGS84, GCJ02, strict); err != nil {
+		t.Fatalf("Beijing should convert: %v", err)
+	}
+
+	// 排除的区域不视为境外
+	hk := Position{114.17, 22.30}
+	strict.ExcludeRegions = RegionHongKong
+	if got, err := TransformWithOptions(hk, WGS84, GCJ02, strict); err != nil || !approxPos(got, hk, 0) {
+		t.Fatalf("excluded Hong Kong: %v (%v)", got, err)
+	}
+}
+
+func TestStrictValidation(t *testing.T) {
+	strict := TransformOptions{Strict: true}
+	for _, p := range []Position{{math.NaN(), 30}, {116, math.Inf(1)}, {116, 91}, {200, 30}, {-180.5, 0}} {
+		if _, err := TransformWithOptions(p, WGS84, GCJ02, strict); GetErrorType(err) != ErrInvalidInput {
+			t.Fatalf("%v: expected ErrInvalidInput, got %v", p, err)
+		}
+		if _, err := Transform(p, WGS84, GCJ02); err != nil {
+			t.Fatalf("%v: lenient mode should not fail: %v", p, err)
+		}
+	}
+
+	// 投影坐标系只检查是否为有限值
+	if _, err := TransformWithOptions(Position{1.2e7, 4.5e6}, EPSG3857, WGS84, strict); err != nil {
+		t.Fatalf("projected source: %v", err)
+	}
+	if _, err := TransformWithOptions(Position{math.NaN(), 0}, EPSG3857, WGS84, strict); GetErrorType(err) != ErrInvalidInput {
+		t.Fatalf("projected NaN: expected ErrInvalidInput, got %v", err)
+	}
+
+	geojson := `{"type":"LineString","coordinates":[[116.4,39.9],[116.5,95]]}`
+	if _, err := TransformWithOptions(geojson, WGS84, BD09, strict); GetErrorType(err) != ErrInvalidInput {
+		t.Fatalf("GeoJSON: expected ErrInvalidInput, got %v", err)
+	}
+
+	conv, _ := NewConverterWithOptions(WGS84, GCJ02, strict)
+	if _, err := conv.Convert(Position{116, 91}); GetErrorType(err) != ErrInvalidInput {
+		t.Fatalf("converter: expected ErrInvalidInput, got %v", err)
+	}
+}
+
+func TestSameCRSOptions(t *testing.T) {
+	// 源与目标相同时选项同样生效
+	if _, err := TransformWithOptions(Position{math.NaN(), 1}, WGS84, WGS84, TransformOptions{Strict: true}); GetErrorType(err) != ErrInvalidInput {
+		t.Fatalf("strict: expected ErrInvalidInput, got %v", err)
+	}
+	if _, err := TransformWithOptions(Position{116.4, 39.9}, "EPSG:4326", WGS84, TransformOptions{Strict: true}); err != nil {
+		t.Fatalf("strict alias: %v", err)
+	}
+	out, err := TransformWithOptions(Position{116.4, 39.9, 50}, WGS84, WGS84, TransformOptions{DropExtraDims: true})
+	if err != nil || len(out) != 2 || out[0] != 116.4 || out[1] != 39.9 {
+		t.Fatalf("DropExtraDims: %v (%v)", out, err)
+	}
+}
+
+func TestBoundsPolicy(t *testing.T) {
+	polar := Position{10, 89}
+	got, err := Transform(polar, WGS84, EPSG3857)
+	if err != nil || got[1] != MaxExtent {
+		t.Fatalf("default should clamp: %v (%v)", got, err)
+	}
+	for _, p := range []Position{polar, {10, -90}, {10, 95}} {
+		if _, err := TransformWithOptions(p, WGS84, EPSG3857, TransformOptions{Bounds: BoundsError}); GetErrorType(err) != ErrInvalidInput {
+			t.Fatalf("%v: expected ErrInvalidInput, got %v", p, err)
+		}
+	}
+	if _, err := TransformWithOptions(Position{116.4, 39.9}, WGS84, EPSG3857, TransformOptions{Bounds: BoundsError}); err != nil {
+		t.Fatalf("in range: %v", err)
+	}
+}
+
+func TestDropExtraDims(t *testing.T) {
+	drop := TransformOptions{DropExtraDims: true}
+
+	point := map[string]any{"type": "Point", "coordinates": []any{116.4, 39.9, 50.0}}
+	out, err := TransformWithOptions(point, WGS84, GCJ02, drop)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c := out["coordinates"].([]any); len(c) != 2 {
+		t.Fatalf("expected 2D coordinates, got %v", c)
+	}
+
+	wkt, err := TransformWithOptions("LINESTRING ZM (116.4 39.9 50 1, 116.5 39.8 60 2)", WGS84, GCJ02, drop)
+	if err != nil || !strings.HasPrefix(wkt, "LINESTRING (") || len(strings.Fields(wkt)) != 5 {
+		t.Fatalf("WKT: %q (%v)", wkt, err)
+	}
+
+	kept, _ := Transform("POINT Z (116.4 39.9 50)", WGS84, GCJ02)
+	if !strings.HasPrefix(kept, "POINT Z (") || !strings.HasSuffix(kept, " 50)") {
+		t.Fatalf("default should keep Z: %q", kept)
+	}
+}
+
+func TestValidatePosition(t *testing.T) {
+	cases := []struct {
+		p     Position
+		crs   CRSTypes
+		value float64
+	}{
+		{Position{1.2e7, 4.5e6}, WGS84, 1.2e7}, // 米误作度
+		{Position{116.4, -91}, GCJ02, -91},
+		{Position{116.4, math.Inf(-1)}, BD09, math.Inf(-1)},
+		{Position{3e7, 0}, EPSG3857, 3e7},
+		{Position{1.3e7, -2.1e7}, BD09MC, -2.1e7},
+	}
+	for _, c := range cases {
+		err := ValidatePosition(c.p, c.crs)
+		te, ok := err.(*TransformError)
+		if !ok || te.Type != ErrInvalidInput || te.Details["value"] != c.value || te.Details["crs"] != c.crs {
+			t.Fatalf("%v %s: unexpected error %#v", c.p, c.crs, err)
+		}
+	}
+	for _, c := range []struct {
+		p   Position
+		crs CRSTypes
+	}{{Position{116.4, 39.9}, WGS84}, {Position{1.2e7, 4.5e6}, EPSG3857}, {Position{1.3e7, 4.8e6}, "bd09mc"}} {
+		if err := ValidatePosition(c.p, c.crs); err != nil {
+			t.Fatalf("%v %s: %v", c.p, c.crs, err)
+		}
+	}
+	if err := ValidatePosition(Position{1}, WGS84); GetErrorType(err) != ErrInvalidInput {
+		t.Fatalf("short position: %v", err)
+	}
+}
+
+func TestStrictErrorPath(t *testing.T) {
+	fc := `{"type":"FeatureCollection","features":[
+		{"type":"Feature","geometry":{"type":"Point","coordinates":[116.4,39.9]}},
+		{"type":"Feature","geometry":{"type":"GeometryCollection","geometries":[
+			{"type":"Point","coordinates":[116.4,39.9]},
+			{"type":"Polygon","coordinates":[[[116.4,39.9],[116.5,39.9],[116.5,95],[116.4,39.9]]]}
+		]}}]}`
+	const want = "$.features[1].geometry.geometries[1].coordinates[0][2]"
+
+	for _, workers := range []int{0, 4} {
+		_, err := TransformWithOptions(fc, WGS84, GCJ02, TransformOptions{Strict: true, Workers: workers})
+		te, ok := err.(*TransformError)
+		if !ok || te.Details["path"] != want || te.Details["value"] != 95.0 {
+			t.Fatalf("workers=%d: unexpected error %#v", workers, err)
+		}
+		if !strings.Contains(te.Message, want) {
+			t.Fatalf("message should contain path: %s", te.Message)
+		}
+	}
+
+	var out strings.Builder
+	err := TransformStreamWithOptions(strings.NewReader(fc), &out, WGS84, GCJ02, TransformOptions{Strict: true})
+	if te, ok := err.(*TransformError); !ok || te.Details["path"] != want {
+		t.Fatalf("stream: unexpected error %#v", err)
+	}
+
+	lines := `{"type":"Point","coordinates":[116.4,39.9]}` + "\n" + `{"type":"LineString","coordinates":[[116.4,39.9],[200,39.9]]}` + "\n"
+	lineErrs, err := TransformLinesWithOptions(strings.NewReader(lines), &out, WGS84, GCJ02, FormatNDJSON, TransformOptions{Strict: true})
+	if err != nil || len(lineErrs) != 1 {
+		t.Fatalf("lines: %v %v", lineErrs, err)
+	}
+	if te, ok := lineErrs[0].Err.(*TransformError); !ok || lineErrs[0].Line != 2 || te.Details["path"] != "$.coordinates[1]" {
+		t.Fatalf("lines: unexpected error %#v", lineErrs[0].Err)
+	}
+}
@@ -0,0 +1,169 @@
+package gcoord
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Polyline 编码折线字符串（Google Encoded Polyline Algorithm，高德、百度等路径接口同样使用）。
+//
+// Transform 接受 Polyline 类型并返回转换后重新编码的 Polyline，
+// 精度由 TransformOptions.PolylinePrecision 指定，默认为 5：
+//
+//	out, err := Transform(Polyline("_p~iF~ps|U_ulLnnqC_mqNvxq`@"), GCJ02, WGS84)
+type Polyline string
+
+// DefaultPolylinePrecision 默认的折线精度，即坐标保留 5 位小数
+const DefaultPolylinePrecision = 5
+
+// maxPolylinePrecision 最大折线精度，超过时坐标放大后可能溢出
+const maxPolylinePrecision = 10
+
+// DecodePolyline 解码编码折线，返回 [lon, lat] 形式的坐标。
+//
+// precision 为坐标小数位数，常用 5（Google、高德）或 6（OSRM、Valhalla），
+// 为 0 时使用 DefaultPolylinePrecision。编码中的坐标顺序为纬度在前。
+func DecodePolyline(s string, precision int) ([]Position, error) {
+	factor, err := polylineFactor(precision)
+	if err != nil {
+		return nil, err
+	}
+
+	var points []Position
+	var lat, lon int64
+	for i := 0; i < len(s); {
+		dlat, next, err := decodePolylineValue(s, i)
+		if err != nil {
+			return nil, err
+		}
+		dlon, next, err := decodePolylineValue(s, next)
+		if err != nil {
+			return nil, err
+		}
+		i = next
+		lat += dlat
+		lon += dlon
+		points = append(points, Position{float64(lon) / factor, float64(lat) / factor})
+	}
+	return points, nil
+}
+
+// EncodePolyline 将 [lon, lat] 形式的坐标编码为折线字符串，
+// precision 规则同 DecodePolyline
+func EncodePolyline(points []Position, precision int) (string, error) {
+	factor, err := polylineFactor(precision)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	var prevLat, prevLon int64
+	for _, p := range points {
+		if err := validatePosition(p); err != nil {
+			return "", err
+		}
+		if math.IsNaN(p[0]) || math.IsNaN(p[1]) || math.IsInf(p[0], 0) || math.IsInf(p[1], 0) {
+			return "", ErrInvalidPosition
+		}
+		lat := int64(math.Round(p[1] * factor))
+		lon := int64(math.Round(p[0] * factor))
+		encodePolylineValue(&b, lat-prevLat)
+		encodePolylineValue(&b, lon-prevLon)
+		prevLat, prevLon = lat, lon
+	}
+	return b.String(), nil
+}
+
+// TransformPolyline 解码折线，将坐标从 crsFrom 转换到 crsTo 后按相同精度重新编码
+func TransformPolyline(s string, crsFrom, crsTo CRSTypes, precision int) (string, error) {
+	out, err := TransformWithOptions(Polyline(s), crsFrom, crsTo, TransformOptions{PolylinePrecision: precision})
+	return string(out), err
+}
+
+// transformPolyline 解码、转换并重新编码折线
+func transformPolyline(s Polyline, gt *geoJSONTransformer) (Polyline, error) {
+	precision := gt.opts.PolylinePrecision
+	points, err := DecodePolyline(string(s), precision)
+	if err != nil {
+		return "", err
+	}
+	for _, p := range points {
+		if p[0], p[1], err = gt.point(p[0], p[1]); err != nil {
+			return "", err
+		}
+	}
+	out, err := EncodePolyline(points, precision)
+	return Polyline(out), err
+}
+
+// polylineFactor 返回精度对应的缩放系数
+func polylineFactor(precision int) (float64, error) {
+	if precision == 0 {
+		precision = DefaultPolylinePrecision
+	}
+	if precision < 0 || precision > maxPolylinePrecision {
+		return 0, &TransformError{
+			Type:    ErrInvalidInput,
+			Message: fmt.Sprintf("折线精度必须在 1 到 %d 之间: %d", maxPolylinePrecision, precision),
+			Details: map[string]interface{}{
+				"precision": precision,
+			},
+		}
+	}
+	return math.Pow10(precision), nil
+}
+
+// decodePolylineValue 从 s[i:] 解码一个有符号整数，返回值与下一个值的起始位置
+func decodePolylineValue(s string, i int) (int64, int, error) {
+	var result uint64
+	for shift := uint(0); ; shift += 5 {
+		if i >= len(s) {
+			return 0, i, errPolylineParse(s, i, "数据不完整")
+		}
+		c := int(s[i]) - 63
+		if c < 0 || c > 63 {
+			return 0, i, errPolylineParse(s, i, fmt.Sprintf("无效的字符 %q", s[i]))
+		}
+		// 第 13 组只剩 4 位，且不能再有后续分组
+		if shift == 60 && c > 0x0f {
+			return 0, i, errPolylineParse(s, i, "数值超出 64 位整数范围")
+		}
+		i++
+		result |= uint64(c&0x1f) << shift
+		if c < 0x20 {
+			break
+		}
+	}
+	// 最低位为符号位
+	v := int64(result >> 1)
+	if result&1 != 0 {
+		v = ^v
+	}
+	return v, i, nil
+}
+
+// encodePolylineValue 编码一个有符号整数
+func encodePolylineValue(b *strings.Builder, v int64) {
+	u := uint64(v) << 1
+	if v < 0 {
+		u = ^u
+	}
+	for u >= 0x20 {
+		b.WriteByte(byte(0x20|(u&0x1f)) + 63)
+		u >>= 5
+	}
+	b.WriteByte(byte(u) + 63)
+}
+
+// errPolylineParse 创建折线解析失败错误，offset 为出错位置
+func errPolylineParse(s string, offset int, msg string) *TransformError {
+	return &TransformError{
+		Type:    ErrInvalidInput,
+		Message: fmt.Sprintf("折线解析失败: %s (位置 %d)", msg, offset),
+		Details: map[string]interface{}{
+			"offset":   offset,
+			"polyline": s,
+		},
+	}
+}
@@ -0,0 +1,95 @@
+package gcoord
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+// Google 文档中的示例折线
+const googlePolyline = "_p~iF~ps|U_ulLnnqC_mqNvxq`@"
+
+var googlePoints = []Position{{-120.2, 38.5}, {-120.95, 40.7}, {-126.453, 43.252}}
+
+func TestDecodePolyline(t *testing.T) {
+	points, err := DecodePolyline(googlePolyline, 5)
+	if err != nil {
+		t.Fatalf("decode error: %v", err)
+	}
+	if len(points) != len(googlePoints) {
+		t.Fatalf("got %d points, want %d", len(points), len(googlePoints))
+	}
+	for i := range points {
+		if !approxPos(points[i], googlePoints[i], 1e-9) {
+			t.Fatalf("point %d: got %v want %v", i, points[i], googlePoints[i])
+		}
+	}
+
+	// 第 13 组超出 64 位或仍有后续分组
+	overflow := strings.Repeat("_", 12) + "O?"
+	for _, bad := range []string{"_p~iF~ps|U_", "_p~iF\x01ps|U", overflow, strings.Repeat("_", 13) + "??"} {
+		if _, err := DecodePolyline(bad, 5); GetErrorType(err) != ErrInvalidInput {
+			t.Fatalf("expected ErrInvalidInput for %q, got %v", bad, err)
+		}
+	}
+	// 64 位整数的边界值仍可往返
+	for _, v := range []int64{math.MaxInt64, math.MinInt64} {
+		var b strings.Builder
+		encodePolylineValue(&b, v)
+		if got, _, err := decodePolylineValue(b.String(), 0); err != nil || got != v {
+			t.Fatalf("%d: got %d (%v)", v, got, err)
+		}
+	}
+	if _, err := DecodePolyline(googlePolyline, 11); err == nil {
+		t.Fatal("expected error for invalid precision")
+	}
+}
+
+func TestEncodePolyline(t *testing.T) {
+	s, err := EncodePolyline(googlePoints, 0)
+	if err != nil {
+		t.Fatalf("encode error: %v", err)
+	}
+	if s != googlePolyline {
+		t.Fatalf("got %q want %q", s, googlePolyline)
+	}
+
+	// 精度 6 往返
+	s6, _ := EncodePolyline(googlePoints, 6)
+	back, err := DecodePolyline(s6, 6)
+	if err != nil {
+		t.Fatalf("decode error: %v", err)
+	}
+	for i := range back {
+		if !approxPos(back[i], googlePoints[i], 1e-9) {
+			t.Fatalf("precision 6 round trip: got %v want %v", back[i], googlePoints[i])
+		}
+	}
+}
+
+func TestTransformPolyline(t *testing.T) {
+	route := []Position{{116.397, 39.908}, {116.404, 39.915}, {121.473, 31.230}}
+	for _, precision := range []int{5, 6} {
+		in, _ := EncodePolyline(route, precision)
+		out, err := TransformPolyline(in, GCJ02, WGS84, precision)
+		if err != nil {
+			t.Fatalf("transform error: %v", err)
+		}
+		points, err := DecodePolyline(out, precision)
+		if err != nil {
+			t.Fatalf("decode error: %v", err)
+		}
+		tol := 1.5 / math.Pow10(precision)
+		for i, p := range points {
+			want, _ := Transform(route[i], GCJ02, WGS84)
+			if !approxPos(p, want, tol) {
+				t.Fatalf("precision %d point %d: got %v want %v", precision, i, p, want)
+			}
+		}
+
+		viaTransform, err := TransformWithOptions(Polyline(in), GCJ02, WGS84, TransformOptions{PolylinePrecision: precision})
+		if err != nil || string(viaTransform) != out {
+			t.Fatalf("Transform mismatch: %q vs %q (%v)", viaTransform, out, err)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package gcoord
+
+import "fmt"
+
+// registerRegionalCRS 注册香港与台湾的坐标系
+func registerRegionalCRS() {
+	// 香港 1980，到 WGS84 的七参数取自香港地政总署测绘处（位置矢量约定，对应 EPSG:1825），精度约 1 米
+	mustRegisterDatum(CRSInfo{
+		Name:        HK1980,
+		Description: "香港1980坐标系，国际1924椭球",
+		Aliases:     []string{"EPSG:4611", "Hong Kong 1980", "HK80"},
+	}, Datum{
+		Ellipsoid: International1924Ellipsoid,
+		ToWGS84: Helmert{
+			TX: -162.619, TY: -276.959, TZ: -161.764,
+			RX: 0.067753, RY: -2.243649, RZ: -1.158827,
+			S: -1.094246,
+		},
+	})
+	// 原点 22°18'43.68"N, 114°10'42.80"E
+	mustRegisterTransverseMercator(CRSInfo{
+		Name:        HK1980Grid,
+		Description: "香港1980方格网",
+		Aliases:     []string{"EPSG:2326", "Hong Kong 1980 Grid System", "HK80 Grid"},
+		Extent:      [4]float64{700000, 700000, 1000000, 1000000},
+	}, HK1980, TransverseMercator{
+		CentralMeridian:  114 + 10/60.0 + 42.80/3600,
+		LatitudeOfOrigin: 22 + 18/60.0 + 43.68/3600,
+		FalseEasting:     836694.05,
+		FalseNorthing:    819069.80,
+	})
+
+	// TWD97 基于 ITRF94，与 WGS84 相差在厘米级，按恒等转换处理
+	mustRegisterDatum(CRSInfo{
+		Name:        TWD97,
+		Description: "台湾1997大地基准，GRS80椭球",
+		Aliases:     []string{"EPSG:3824"},
+	}, Datum{Ellipsoid: GRS80Ellipsoid})
+	for _, zone := range []struct {
+		name     CRSTypes
+		cm, code int
+		desc     string
+	}{
+		{TWD97TM2, 121, 3826, "台湾本岛"},
+		{TWD97TM2Zone119, 119, 3825, "澎湖、金门、马祖"},
+	} {
+		mustRegisterTransverseMercator(CRSInfo{
+			Name:        zone.name,
+			Description: fmt.Sprintf("TWD97 二度分带横轴墨卡托投影，中央经线 %d°E（%s）", zone.cm, zone.desc),
+			Aliases:     []string{fmt.Sprintf("EPSG:%d", zone.code), fmt.Sprintf("TWD97 / TM2 zone %d", zone.cm)},
+			Extent:      [4]float64{0, 2000000, 500000, 3200000},
+		}, TWD97, TransverseMercator{
+			CentralMeridian: float64(zone.cm),
+			ScaleFactor:     0.9999,
+			FalseEasting:    250000,
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package gcoord
+
+import "testing"
+
+func TestHK1980Grid(t *testing.T) {
+	origin := Position{836694.05, 819069.80}
+	lon0 := 114 + 10/60.0 + 42.80/3600
+	lat0 := 22 + 18/60.0 + 43.68/3600
+
+	// 方格网原点
+	geo, err := Transform(origin, HK1980Grid, HK1980)
+	if err != nil {
+		t.Fatalf("HK1980Grid -> HK1980: %v", err)
+	}
+	if !approxPos(geo, Position{lon0, lat0}, 1e-9) {
+		t.Fatalf("grid origin = %v, want %v, %v", geo, lon0, lat0)
+	}
+
+	// 测绘处给出的近似关系：WGS84 纬度减 5.5 秒、经度加 8.8 秒，误差约 2 米
+	wgs, err := Transform(origin, EPSG2326, WGS84)
+	if err != nil {
+		t.Fatalf("HK1980Grid -> WGS84: %v", err)
+	}
+	if !approx(wgs[0], lon0+8.8/3600, 0.2/3600) || !approx(wgs[1], lat0-5.5/3600, 0.2/3600) {
+		t.Fatalf("HK1980Grid -> WGS84 = %v", wgs)
+	}
+
+	// 直接转换到 GCJ02、BD09 并往返，BD09 默认反解误差为厘米级
+	for _, to := range []CRSTypes{GCJ02, BD09} {
+		out, err := Transform(origin, HK1980Grid, to)
+		if err != nil {
+			t.Fatalf("HK1980Grid -> %s: %v", to, err)
+		}
+		back, err := Transform(out, to, HK1980Grid)
+		if err != nil || !approx(back[0], origin[0], 0.1) || !approx(back[1], origin[1], 0.1) {
+			t.Fatalf("%s -> HK1980Grid = %v, %v", to, back, err)
+		}
+	}
+}
+
+func TestTWD97TM2(t *testing.T) {
+	// 中央经线与赤道交点
+	if xy, _ := Transform(Position{121, 0}, TWD97, TWD97TM2); !approx(xy[0], 250000, 1e-6) || !approx(xy[1], 0, 1e-6) {
+		t.Fatalf("TM2 origin = %v", xy)
+	}
+
+	// 台北 101，东坐标约 307 千米，北坐标约 2770 千米
+	p := Position{121.5645, 25.0339}
+	xy, err := Transform(p, WGS84, EPSG3826)
+	if err != nil {
+		t.Fatalf("WGS84 -> TWD97TM2: %v", err)
+	}
+	if !approx(xy[0], 306966, 10) || !approx(xy[1], 2769651, 10) {
+		t.Fatalf("Taipei 101 in TM2 = %v", xy)
+	}
+	gcj, err := Transform(xy, TWD97TM2, GCJ02)
+	if err != nil {
+		t.Fatalf("TWD97TM2 -> GCJ02: %v", err)
+	}
+	if want, _ := Transform(p, WGS84, GCJ02); !approxPos(gcj, want, 1e-9) {
+		t.Fatalf("TWD97TM2 -> GCJ02 = %v, want %v", gcj, want)
+	}
+
+	// 澎湖使用 119°E 分带
+	if xy, _ := Transform(Position{119.5664, 23.5655}, WGS84, TWD97TM2Zone119); !approx(xy[0], 307700, 1000) {
+		t.Fatalf("Penghu in TM2 zone 119 = %v", xy)
+	}
+}
+
+func TestRegionalCRSListed(t *testing.T) {
+	for code, crs := range map[string]CRSTypes{
+		"EPSG:4611": HK1980,
+		"EPSG:2326": HK1980Grid,
+		"EPSG:3824": TWD97,
+		"EPSG:3826": TWD97TM2,
+		"EPSG:3825": TWD97TM2Zone119,
+	} {
+		if parsed, err := ParseCRS(code); err != nil || parsed != crs {
+			t.Errorf("ParseCRS(%s) = %v, %v, want %v", code, parsed, err, crs)
+		}
+	}
+
+	listed := map[CRSTypes]bool{}
+	for _, info := range ListCRS() {
+		listed[info.Name] = true
+	}
+	for _, crs := range []CRSTypes{HK1980Grid, TWD97TM2} {
+		if !listed[crs] {
+			t.Errorf("%s missing from ListCRS", crs)
+		}
+	}
+}
@@ -1,78 +1,341 @@
 package gcoord
 
 import (
+	"fmt"
+	"math"
 	"sync"
 )
 
 // Converter 将一个坐标转换为另一个坐标
 type Converter func(Position) Position
 
-// crsMap 记录从某 CRS 到其他 CRS 的转换函数
-var crsMap = map[CRSTypes]map[CRSTypes]Converter{}
+// PointConverter 转换单个二维点，不分配内存，用于批量转换
+type PointConverter func(x, y float64) (float64, float64)
+
+// pointStep 转换图中边的内部形式，读取每次调用的选项并可返回错误，
+// Converter 与 PointConverter 注册时均包装为 pointStep
+type pointStep func(x, y float64, o *TransformOptions) (float64, float64, error)
+
+// defaultOptions 不带选项的接口使用的默认选项，只读
+var defaultOptions = &TransformOptions{}
+
+// CRSInfo 描述一个已注册的坐标系
+type CRSInfo struct {
+	// Name 坐标系名称，即 CRSTypes 的取值
+	Name CRSTypes
+	// Description 坐标系说明
+	Description string
+	// Aliases 坐标系别名
+	Aliases []string
+	// Projected 是否为投影坐标系（单位为米）
+	Projected bool
+	// Precision 转换到该坐标系的精度，为 0 时按是否投影取默认值
+	Precision float64
+	// Extent 坐标的有效范围 [minX, minY, maxX, maxY]，用于严格模式下校验源坐标，
+	// 为零值时按是否投影取默认值：经纬度为 [-180, -90, 180, 90]，投影坐标为 ±MaxExtent
+	Extent [4]float64
+}
+
+// 坐标系注册表
+var (
+	registryMutex sync.RWMutex
+	crsInfos      = map[CRSTypes]CRSInfo{}
+	crsOrder      []CRSTypes
+	// crsAliases 规范化名称及别名到坐标系的索引，见 normalizeCRSName
+	crsAliases = map[string]CRSTypes{}
+)
+
+// converterEdge 转换图中的一条边，step 由注册时提供的函数包装而来
+type converterEdge struct {
+	to   CRSTypes
+	step pointStep
+	cost float64
+}
+
+// crsMap 转换图：记录从某 CRS 直接到其他 CRS 的转换函数，
+// 任意两个 CRS 之间的转换路径由 findPath 按代价最小自动推导
+var crsMap = map[CRSTypes][]converterEdge{}
+
+// DefaultConverterCost 通过 RegisterConverter 注册的转换函数的默认代价
+const DefaultConverterCost = 1.0
 
 // 预计算的转换器缓存
+//
+// 加锁顺序：先 cacheMutex 后 registryMutex
 var (
-	converterCache = make(map[string]Converter)
-	cacheMutex     sync.RWMutex
+	stepCache  = make(map[string]pointStep)
+	cacheMutex sync.RWMutex
 )
 
 func init() {
-	// 注册各 CRS 的转换函数
-	crsMap[WGS84] = map[CRSTypes]Converter{
-		GCJ02:    WGS84ToGCJ02,
-		BD09:     compose(GCJ02ToBD09, WGS84ToGCJ02),
-		BD09MC:   compose(BD09toBD09MC, GCJ02ToBD09, WGS84ToGCJ02),
-		EPSG3857: WGS84ToEPSG3857,
-	}
-	crsMap[GCJ02] = map[CRSTypes]Converter{
-		WGS84:    GCJ02ToWGS84,
-		BD09:     GCJ02ToBD09,
-		BD09MC:   compose(BD09toBD09MC, GCJ02ToBD09),
-		EPSG3857: compose(WGS84ToEPSG3857, GCJ02ToWGS84),
-	}
-	crsMap[BD09] = map[CRSTypes]Converter{
-		WGS84:    compose(GCJ02ToWGS84, BD09ToGCJ02),
-		GCJ02:    BD09ToGCJ02,
-		EPSG3857: compose(WGS84ToEPSG3857, GCJ02ToWGS84, BD09ToGCJ02),
-		BD09MC:   BD09toBD09MC,
-	}
-	crsMap[EPSG3857] = map[CRSTypes]Converter{
-		WGS84:  EPSG3857ToWGS84,
-		GCJ02:  compose(WGS84ToGCJ02, EPSG3857ToWGS84),
-		BD09:   compose(GCJ02ToBD09, WGS84ToGCJ02, EPSG3857ToWGS84),
-		BD09MC: compose(BD09toBD09MC, GCJ02ToBD09, WGS84ToGCJ02, EPSG3857ToWGS84),
-	}
-	crsMap[BD09MC] = map[CRSTypes]Converter{
-		WGS84:    compose(GCJ02ToWGS84, BD09ToGCJ02, BD09MCtoBD09),
-		GCJ02:    compose(BD09ToGCJ02, BD09MCtoBD09),
-		EPSG3857: compose(WGS84ToEPSG3857, GCJ02ToWGS84, BD09ToGCJ02, BD09MCtoBD09),
-		BD09:     BD09MCtoBD09,
-	}
-}
-
-// compose 将多个 Converter 组合为一个，从右到左执行
-func compose(funcs ...Converter) Converter {
-	return func(p Position) Position {
-		res := p
-		for i := len(funcs) - 1; i >= 0; i-- {
-			res = funcs[i](res)
+	// 注册内置坐标系
+	mustRegisterCRS(CRSInfo{
+		Name:        WGS84,
+		Description: "世界大地坐标系，GPS原始坐标",
+		Aliases:     []string{"WGS1984", "EPSG4326", "CRS84", "OGC:CRS84"},
+	})
+	mustRegisterCRS(CRSInfo{
+		Name:        GCJ02,
+		Description: "国测局坐标系，中国标准坐标系",
+		Aliases:     []string{"AMap"},
+	})
+	mustRegisterCRS(CRSInfo{
+		Name:        BD09,
+		Description: "百度坐标系",
+		Aliases:     []string{"BD09LL", "Baidu", "BMap"},
+	})
+	mustRegisterCRS(CRSInfo{
+		Name:        BD09MC,
+		Description: "百度墨卡托投影坐标系",
+		Aliases:     []string{"BD09Meter"},
+		Projected:   true,
+	})
+	mustRegisterCRS(CRSInfo{
+		Name:        EPSG3857,
+		Description: "Web墨卡托投影坐标系",
+		Aliases:     []string{"EPSG900913", "EPSG102100", "WebMercator", "WM"},
+		Projected:   true,
+	})
+
+	// 注册各 CRS 之间的直接转换函数，其余组合由转换图自动推导
+	registerSteps(WGS84, map[CRSTypes]pointStep{
+		GCJ02:    wgs84ToGCJ02Step,
+		EPSG3857: wgs84ToEPSG3857Step,
+	})
+	registerSteps(GCJ02, map[CRSTypes]pointStep{
+		WGS84: gcj02ToWGS84Step,
+		BD09:  stepOf(gcj02ToBD09),
+	})
+	registerSteps(BD09, map[CRSTypes]pointStep{
+		GCJ02:  bd09ToGCJ02Step,
+		BD09MC: stepOf(bd09ToBD09MC),
+	})
+	registerSteps(EPSG3857, map[CRSTypes]pointStep{
+		WGS84: stepOf(epsg3857ToWGS84),
+	})
+	registerSteps(BD09MC, map[CRSTypes]pointStep{
+		BD09: stepOf(bd09MCToBD09),
+	})
+
+	registerDatums()
+	registerRegionalCRS()
+	registerCGCS2000GK()
+	registerUTM()
+}
+
+// RegisterCRS 注册新的坐标系。
+//
+// 注册后即可在 Transform、NewConverter 中使用，但仍需通过 RegisterConverter
+// 注册与其他坐标系之间的转换函数。名称与别名在 ParseCRS 中不区分大小写，
+// 不能与已注册的坐标系冲突。
+func RegisterCRS(info CRSInfo) error {
+	if info.Name == "" {
+		return ErrEmptyCRS
+	}
+	if info.Precision == 0 {
+		info.Precision = LonLatPrecision
+		if info.Projected {
+			info.Precision = ProjectionPrecision
+		}
+	}
+	if info.Extent == [4]float64{} {
+		info.Extent = [4]float64{-180, -90, 180, 90}
+		if info.Projected {
+			info.Extent = [4]float64{-MaxExtent, -MaxExtent, MaxExtent, MaxExtent}
 		}
-		return res
 	}
+	info.Aliases = append([]string(nil), info.Aliases...)
+
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+
+	if _, exists := crsInfos[info.Name]; exists {
+		return ErrCRSAlreadyRegistered(info.Name)
+	}
+	keys := make([]string, 0, len(info.Aliases)+1)
+	for _, name := range append([]string{string(info.Name)}, info.Aliases...) {
+		key := normalizeCRSName(name)
+		if key == "" {
+			continue
+		}
+		if owner, exists := crsAliases[key]; exists && owner != info.Name {
+			return ErrCRSAlreadyRegistered(CRSTypes(name))
+		}
+		keys = append(keys, key)
+	}
+	for _, key := range keys {
+		crsAliases[key] = info.Name
+	}
+	crsInfos[info.Name] = info
+	crsOrder = append(crsOrder, info.Name)
+	return nil
+}
+
+// RegisterConverter 注册 from 到 to 的直接转换函数，已存在时覆盖。
+//
+// from 与 to 都必须已通过 RegisterCRS 注册。注册后与其他坐标系之间的
+// 转换路径会自动推导，无需逐一注册。
+func RegisterConverter(from, to CRSTypes, conv Converter) error {
+	return RegisterConverterWithCost(from, to, conv, DefaultConverterCost)
+}
+
+// RegisterConverterWithCost 注册带代价的直接转换函数。
+//
+// 推导转换路径时选择总代价最小的路径，代价可用于表示跳数或精度损失，
+// 例如近似公式可设置更高的代价，使其仅在没有更精确路径时被选用。
+func RegisterConverterWithCost(from, to CRSTypes, conv Converter, cost float64) error {
+	if conv == nil {
+		return errNilConverter(from, to)
+	}
+	return registerEdge(from, to, converterEdge{
+		step: func(x, y float64, _ *TransformOptions) (float64, float64, error) {
+			p := conv(Position{x, y})
+			return p[0], p[1], nil
+		},
+		cost: cost,
+	})
 }
 
-// getConverter 获取或创建转换器，支持缓存
-func getConverter(from, to CRSTypes) Converter {
+// RegisterPointConverter 以 PointConverter 注册 from 到 to 的直接转换函数，
+// 路径全部由 PointConverter 组成时，批量转换不会为每个点分配内存
+func RegisterPointConverter(from, to CRSTypes, conv PointConverter) error {
+	if conv == nil {
+		return errNilConverter(from, to)
+	}
+	return registerEdge(from, to, converterEdge{
+		step: stepOf(conv),
+		cost: DefaultConverterCost,
+	})
+}
+
+// registerEdge 向转换图中添加或替换一条边
+func registerEdge(from, to CRSTypes, edge converterEdge) error {
+	if from == "" || to == "" {
+		return ErrEmptyCRS
+	}
+	from, err := ParseCRS(string(from))
+	if err != nil {
+		return err
+	}
+	to, err = ParseCRS(string(to))
+	if err != nil {
+		return err
+	}
+
+	cacheMutex.Lock()
+	defer cacheMutex.Unlock()
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+
 	if from == to {
-		return func(p Position) Position { return p }
+		return &TransformError{
+			Type:    ErrInvalidCRS,
+			Message: "不能注册到自身的转换函数",
+			Details: map[string]interface{}{
+				"crs": from,
+			},
+		}
+	}
+	if !(edge.cost > 0) {
+		return &TransformError{
+			Type:    ErrInvalidInput,
+			Message: fmt.Sprintf("转换代价必须为正数: %v", edge.cost),
+			Details: map[string]interface{}{
+				"cost": edge.cost,
+			},
+		}
+	}
+
+	edge.to = to
+	replaced := false
+	for i := range crsMap[from] {
+		if crsMap[from][i].to == to {
+			crsMap[from][i] = edge
+			replaced = true
+			break
+		}
+	}
+	if !replaced {
+		crsMap[from] = append(crsMap[from], edge)
+	}
+
+	// 注册表变化后缓存失效
+	stepCache = make(map[string]pointStep)
+	return nil
+}
+
+// LookupCRS 查询已注册的坐标系，支持 ParseCRS 可识别的别名
+func LookupCRS(crs CRSTypes) (CRSInfo, bool) {
+	name, err := ParseCRS(string(crs))
+	if err != nil {
+		return CRSInfo{}, false
+	}
+
+	registryMutex.RLock()
+	defer registryMutex.RUnlock()
+	info, ok := crsInfos[name]
+	if !ok {
+		return CRSInfo{}, false
+	}
+	info.Aliases = append([]string(nil), info.Aliases...)
+	return info, true
+}
+
+// ListCRS 按注册顺序返回所有已注册的坐标系
+func ListCRS() []CRSInfo {
+	registryMutex.RLock()
+	defer registryMutex.RUnlock()
+	list := make([]CRSInfo, 0, len(crsOrder))
+	for _, name := range crsOrder {
+		info := crsInfos[name]
+		info.Aliases = append([]string(nil), info.Aliases...)
+		list = append(list, info)
+	}
+	return list
+}
+
+// mustRegisterCRS 注册内置坐标系，失败时 panic
+func mustRegisterCRS(info CRSInfo) {
+	if err := RegisterCRS(info); err != nil {
+		panic(err)
+	}
+}
+
+// registerSteps 批量注册内置转换函数，失败时 panic
+func registerSteps(from CRSTypes, steps map[CRSTypes]pointStep) {
+	for to, step := range steps {
+		err := registerEdge(from, to, converterEdge{step: step, cost: DefaultConverterCost})
+		if err != nil {
+			panic(err)
+		}
+	}
+}
+
+// stepOf 将不会失败的 PointConverter 包装为 pointStep
+func stepOf(conv PointConverter) pointStep {
+	return func(x, y float64, _ *TransformOptions) (float64, float64, error) {
+		x, y = conv(x, y)
+		return x, y, nil
+	}
+}
+
+// identityStep 恒等转换
+func identityStep(x, y float64, _ *TransformOptions) (float64, float64, error) {
+	return x, y, nil
+}
+
+// getStep 获取或创建带选项的转换函数，支持缓存，不可达时返回 nil
+func getStep(from, to CRSTypes) pointStep {
+	if from == to {
+		return identityStep
 	}
 
 	key := string(from) + "->" + string(to)
 
 	cacheMutex.RLock()
-	if converter, exists := converterCache[key]; exists {
+	if step, exists := stepCache[key]; exists {
 		cacheMutex.RUnlock()
-		return converter
+		return step
 	}
 	cacheMutex.RUnlock()
 
@@ -80,36 +343,159 @@ func getConverter(from, to CRSTypes) Converter {
 	defer cacheMutex.Unlock()
 
 	// 双重检查
-	if converter, exists := converterCache[key]; exists {
-		return converter
+	if step, exists := stepCache[key]; exists {
+		return step
 	}
 
-	// 创建转换器
-	converter := buildConverter(from, to)
-	if converter != nil {
-		converterCache[key] = converter
+	registryMutex.RLock()
+	edges := findPath(from, to)
+	registryMutex.RUnlock()
+	if edges == nil {
+		return nil
 	}
-	return converter
+	step := chainStep(edges)
+	stepCache[key] = step
+	return step
 }
 
-// buildConverter 构建转换器
-func buildConverter(from, to CRSTypes) Converter {
-	fromMap, ok := crsMap[from]
-	if !ok {
-		return nil
+// chainStep 按路径顺序串联各边的 pointStep，任一步出错时停止
+func chainStep(edges []converterEdge) pointStep {
+	if len(edges) == 1 {
+		return edges[0].step
+	}
+	steps := make([]pointStep, len(edges))
+	for i, e := range edges {
+		steps[i] = e.step
+	}
+	return func(x, y float64, o *TransformOptions) (float64, float64, error) {
+		var err error
+		for _, f := range steps {
+			if x, y, err = f(x, y, o); err != nil {
+				return x, y, err
+			}
+		}
+		return x, y, nil
 	}
+}
 
-	conv, ok := fromMap[to]
-	if !ok || conv == nil {
+// findPath 使用 Dijkstra 算法查找 from 到 to 总代价最小的路径，
+// 代价相同时选择跳数更少的路径，不可达时返回 nil。
+// 调用方需持有 registryMutex。
+func findPath(from, to CRSTypes) []converterEdge {
+	if _, ok := crsInfos[from]; !ok {
 		return nil
 	}
+	if _, ok := crsInfos[to]; !ok {
+		return nil
+	}
+
+	type node struct {
+		cost  float64
+		hops  int
+		prev  CRSTypes
+		edge  converterEdge
+		done  bool
+		found bool
+	}
+	nodes := make(map[CRSTypes]*node, len(crsOrder))
+	for _, name := range crsOrder {
+		nodes[name] = &node{cost: math.Inf(1)}
+	}
+	nodes[from].cost = 0
+	nodes[from].found = true
+
+	for {
+		// 按注册顺序选取未完成的最小代价节点，保证结果确定
+		var cur CRSTypes
+		var best *node
+		for _, name := range crsOrder {
+			n := nodes[name]
+			if n.done || !n.found {
+				continue
+			}
+			if best == nil || n.cost < best.cost || (n.cost == best.cost && n.hops < best.hops) {
+				cur, best = name, n
+			}
+		}
+		if best == nil {
+			return nil
+		}
+		if cur == to {
+			break
+		}
+		best.done = true
+
+		for _, e := range crsMap[cur] {
+			next := nodes[e.to]
+			cost := best.cost + e.cost
+			if next.done {
+				continue
+			}
+			if !next.found || cost < next.cost || (cost == next.cost && best.hops+1 < next.hops) {
+				next.cost = cost
+				next.hops = best.hops + 1
+				next.prev = cur
+				next.edge = e
+				next.found = true
+			}
+		}
+	}
+
+	path := make([]converterEdge, nodes[to].hops)
+	for cur, i := to, len(path)-1; cur != from; i-- {
+		path[i] = nodes[cur].edge
+		cur = nodes[cur].prev
+	}
+	return path
+}
+
+// ConversionPath 返回 from 到 to 的转换路径（包含首尾坐标系）
+func ConversionPath(from, to CRSTypes) ([]CRSTypes, error) {
+	from, err := validateCRS(from)
+	if err != nil {
+		return nil, err
+	}
+	to, err = validateCRS(to)
+	if err != nil {
+		return nil, err
+	}
+	if from == to {
+		return []CRSTypes{from}, nil
+	}
+
+	registryMutex.RLock()
+	defer registryMutex.RUnlock()
+
+	edges := findPath(from, to)
+	if edges == nil {
+		return nil, ErrNoConverter(from, to)
+	}
+	path := []CRSTypes{from}
+	for _, e := range edges {
+		path = append(path, e.to)
+	}
+	return path, nil
+}
+
+// isRegisteredCRS 检查坐标系是否已注册
+func isRegisteredCRS(crs CRSTypes) bool {
+	registryMutex.RLock()
+	defer registryMutex.RUnlock()
+	_, ok := crsInfos[crs]
+	return ok
+}
 
-	return conv
+// lookupCRSAlias 按规范化名称查找坐标系
+func lookupCRSAlias(key string) (CRSTypes, bool) {
+	registryMutex.RLock()
+	defer registryMutex.RUnlock()
+	crs, ok := crsAliases[key]
+	return crs, ok
 }
 
 // ClearCache 清空转换器缓存（主要用于测试）
 func ClearCache() {
 	cacheMutex.Lock()
 	defer cacheMutex.Unlock()
-	converterCache = make(map[string]Converter)
+	stepCache = make(map[string]pointStep)
 }
@@ -0,0 +1,254 @@
+package gcoord
+
+import (
+	"slices"
+	"testing"
+)
+
+// unregisterCRS 从注册表中移除测试注册的坐标系及相关的边与基准，
+// 供 t.Cleanup 调用，使测试可重复运行（go test -count=2）
+func unregisterCRS(names ...CRSTypes) {
+	cacheMutex.Lock()
+	defer cacheMutex.Unlock()
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+
+	for _, name := range names {
+		delete(crsInfos, name)
+		delete(datums, name)
+		delete(crsMap, name)
+		crsOrder = slices.DeleteFunc(crsOrder, func(c CRSTypes) bool { return c == name })
+		for key, owner := range crsAliases {
+			if owner == name {
+				delete(crsAliases, key)
+			}
+		}
+		for from, edges := range crsMap {
+			crsMap[from] = slices.DeleteFunc(edges, func(e converterEdge) bool { return e.to == name })
+		}
+	}
+	stepCache = make(map[string]pointStep)
+}
+
+func TestRegisterCRS(t *testing.T) {
+	const local CRSTypes = "TEST_LOCAL_GRID"
+	t.Cleanup(func() { unregisterCRS(local) })
+	err := RegisterCRS(CRSInfo{
+		Name:        local,
+		Description: "测试用局部网格",
+		Projected:   true,
+	})
+	if err != nil {
+		t.Fatalf("register crs error: %v", err)
+	}
+	if err := RegisterCRS(CRSInfo{Name: local}); err == nil {
+		t.Fatalf("expect error on duplicate registration")
+	}
+
+	info, ok := LookupCRS(local)
+	if !ok {
+		t.Fatalf("registered crs not found")
+	}
+	if info.Precision != ProjectionPrecision {
+		t.Fatalf("default precision mismatch: got %v", info.Precision)
+	}
+
+	// 注册转换函数前不可转换
+	if _, err := Transform(Position{1000, 2000}, local, WGS84); err == nil {
+		t.Fatalf("expect error without converter")
+	}
+
+	// 以 EPSG3857 平移 1000 米作为局部网格
+	toLocal := func(p Position) Position {
+		m := WGS84ToEPSG3857(p)
+		return Position{m[0] - 1000, m[1] - 1000}
+	}
+	fromLocal := func(p Position) Position {
+		return EPSG3857ToWGS84(Position{p[0] + 1000, p[1] + 1000})
+	}
+	if err := RegisterConverter(WGS84, local, toLocal); err != nil {
+		t.Fatalf("register converter error: %v", err)
+	}
+	if err := RegisterConverter(local, WGS84, fromLocal); err != nil {
+		t.Fatalf("register converter error: %v", err)
+	}
+
+	src := Position{116.397, 39.908}
+	grid, err := Transform(src, WGS84, local)
+	if err != nil {
+		t.Fatalf("wgs->local error: %v", err)
+	}
+	back, err := Transform(grid, local, WGS84)
+	if err != nil {
+		t.Fatalf("local->wgs error: %v", err)
+	}
+	if !approxPos(back, src, TestPrecisionRoundtrip) {
+		t.Fatalf("roundtrip mismatch: got %v want %v", back, src)
+	}
+
+	conv, err := NewConverter(WGS84, local)
+	if err != nil {
+		t.Fatalf("new converter error: %v", err)
+	}
+	if conv.GetPrecision() != ProjectionPrecision {
+		t.Fatalf("converter precision mismatch: got %v", conv.GetPrecision())
+	}
+
+	found := false
+	for _, info := range ListCRS() {
+		if info.Name == local {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("registered crs missing from ListCRS")
+	}
+}
+
+func TestRegisterConverterUnknownCRS(t *testing.T) {
+	err := RegisterConverter(WGS84, "TEST_UNKNOWN", WGS84ToGCJ02)
+	if GetErrorType(err) != ErrInvalidCRS {
+		t.Fatalf("expect ErrInvalidCRS, got %v", err)
+	}
+}
+
+func TestConversionPath(t *testing.T) {
+	path, err := ConversionPath(BD09MC, EPSG3857)
+	if err != nil {
+		t.Fatalf("path error: %v", err)
+	}
+	want := []CRSTypes{BD09MC, BD09, GCJ02, WGS84, EPSG3857}
+	if len(path) != len(want) {
+		t.Fatalf("path mismatch: got %v want %v", path, want)
+	}
+	for i := range want {
+		if path[i] != want[i] {
+			t.Fatalf("path mismatch: got %v want %v", path, want)
+		}
+	}
+
+	// 自动推导的路径应与手工组合一致
+	src := Position{12960000, 4830000}
+	got, err := Transform(src, BD09MC, EPSG3857)
+	if err != nil {
+		t.Fatalf("bd09mc->3857 error: %v", err)
+	}
+	manual := WGS84ToEPSG3857(GCJ02ToWGS84(BD09ToGCJ02(BD09MCtoBD09(src))))
+	if !approxPos(got, manual, 0) {
+		t.Fatalf("derived converter mismatch: got %v want %v", got, manual)
+	}
+}
+
+func TestConversionPathPrefersLowerCost(t *testing.T) {
+	const a, b, c CRSTypes = "TEST_PATH_A", "TEST_PATH_B", "TEST_PATH_C"
+	t.Cleanup(func() { unregisterCRS(a, b, c) })
+	for _, crs := range []CRSTypes{a, b, c} {
+		if err := RegisterCRS(CRSInfo{Name: crs}); err != nil {
+			t.Fatalf("register crs error: %v", err)
+		}
+	}
+	shift := func(d float64) Converter {
+		return func(p Position) Position { return Position{p[0] + d, p[1]} }
+	}
+
+	// A->C 直连但代价高，A->B->C 两跳但总代价低
+	mustNoError(t, RegisterConverterWithCost(a, c, shift(100), 5))
+	mustNoError(t, RegisterConverter(a, b, shift(1)))
+	mustNoError(t, RegisterConverter(b, c, shift(2)))
+
+	path, err := ConversionPath(a, c)
+	if err != nil {
+		t.Fatalf("path error: %v", err)
+	}
+	if len(path) != 3 || path[1] != b {
+		t.Fatalf("expect path through %s, got %v", b, path)
+	}
+	out, err := Transform(Position{0, 0}, a, c)
+	if err != nil {
+		t.Fatalf("transform error: %v", err)
+	}
+	if out[0] != 3 {
+		t.Fatalf("expect cheaper path result 3, got %v", out[0])
+	}
+
+	// 降低直连代价后应改走直连，且缓存随注册失效
+	mustNoError(t, RegisterConverterWithCost(a, c, shift(100), 1.5))
+	out, err = Transform(Position{0, 0}, a, c)
+	if err != nil {
+		t.Fatalf("transform error: %v", err)
+	}
+	if out[0] != 100 {
+		t.Fatalf("expect direct edge result 100, got %v", out[0])
+	}
+
+	if _, err := ConversionPath(c, a); GetErrorType(err) != ErrInvalidCRS {
+		t.Fatalf("expect unreachable error, got %v", err)
+	}
+}
+
+func mustNoError(t *testing.T, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestParseCRS(t *testing.T) {
+	cases := map[string]CRSTypes{
+		"WGS84":                         WGS84,
+		"wgs84":                         WGS84,
+		" WGS1984 ":                     WGS84,
+		"EPSG:4326":                     WGS84,
+		"epsg4326":                      WGS84,
+		"urn:ogc:def:crs:EPSG::4326":    WGS84,
+		"urn:ogc:def:crs:OGC:1.3:CRS84": WGS84,
+		"http://www.opengis.net/def/crs/EPSG/0/4326": WGS84,
+		"AMap":                          GCJ02,
+		"amap":                          GCJ02,
+		"BMap":                          BD09,
+		"bd09ll":                        BD09,
+		"Baidu":                         BD09,
+		"bd09_meter":                    BD09MC,
+		"WebMercator":                   EPSG3857,
+		"EPSG:3857":                     EPSG3857,
+		"EPSG:900913":                   EPSG3857,
+		"urn:ogc:def:crs:EPSG::3857":    EPSG3857,
+		"urn:ogc:def:crs:EPSG:6.3:3857": EPSG3857,
+	}
+	for in, want := range cases {
+		got, err := ParseCRS(in)
+		if err != nil {
+			t.Fatalf("ParseCRS(%q) error: %v", in, err)
+		}
+		if got != want {
+			t.Fatalf("ParseCRS(%q) = %s, want %s", in, got, want)
+		}
+	}
+
+	if _, err := ParseCRS(""); err != ErrEmptyCRS {
+		t.Fatalf("expect ErrEmptyCRS, got %v", err)
+	}
+	if _, err := ParseCRS("EPSG:9999"); GetErrorType(err) != ErrInvalidCRS {
+		t.Fatalf("expect ErrInvalidCRS, got %v", err)
+	}
+}
+
+func TestTransformAcceptsAliases(t *testing.T) {
+	src := Position{116.397, 39.908}
+	want, err := Transform(src, WGS84, BD09)
+	if err != nil {
+		t.Fatalf("transform error: %v", err)
+	}
+	got, err := Transform(src, "epsg:4326", "BMap")
+	if err != nil {
+		t.Fatalf("alias transform error: %v", err)
+	}
+	if !approxPos(got, want, 0) {
+		t.Fatalf("alias transform mismatch: got %v want %v", got, want)
+	}
+
+	// 别名冲突的坐标系不能注册
+	if err := RegisterCRS(CRSInfo{Name: "TEST_ALIAS", Aliases: []string{"amap"}}); err == nil {
+		t.Fatalf("expect error on conflicting alias")
+	}
+}
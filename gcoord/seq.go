@@ -0,0 +1,162 @@
+package gcoord
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// SeqFormat 行式 GeoJSON 的格式
+type SeqFormat int
+
+const (
+	// FormatNDJSON 换行分隔的 JSON，每行一条记录
+	FormatNDJSON SeqFormat = iota
+	// FormatGeoJSONSeq RFC 8142 GeoJSON 文本序列，每条记录以 RS (0x1E) 开头、换行结尾
+	FormatGeoJSONSeq
+)
+
+// recordSeparator RFC 7464 记录分隔符
+const recordSeparator = 0x1E
+
+// LineError 行式转换中单条记录的错误
+type LineError struct {
+	// Line 记录起始行号，从 1 开始
+	Line int
+	Err  error
+}
+
+func (e *LineError) Error() string {
+	return fmt.Sprintf("第 %d 行: %v", e.Line, e.Err)
+}
+
+func (e *LineError) Unwrap() error {
+	return e.Err
+}
+
+// TransformLines 逐条转换行式 GeoJSON（NDJSON 或 GeoJSONSeq），
+// 每条记录读取、转换后立即写出。
+//
+// 单条记录解析或转换失败时跳过该记录并记录带行号的错误，不中断整个流；
+// 返回的 error 仅表示读写失败。
+//
+// 示例：
+//
+//	lineErrs, err := TransformLines(os.Stdin, os.Stdout, WGS84, GCJ02, FormatNDJSON)
+//	for _, le := range lineErrs {
+//	    log.Println(le)
+//	}
+func TransformLines(r io.Reader, w io.Writer, crsFrom, crsTo CRSTypes, format SeqFormat) ([]*LineError, error) {
+	return TransformLinesWithOptions(r, w, crsFrom, crsTo, format, TransformOptions{})
+}
+
+// TransformLinesWithOptions 与 TransformLines 相同，但可通过 opts 控制转换行为
+func TransformLinesWithOptions(r io.Reader, w io.Writer, crsFrom, crsTo CRSTypes, format SeqFormat, opts TransformOptions) ([]*LineError, error) {
+	crsFrom, err := validateCRS(crsFrom)
+	if err != nil {
+		return nil, err
+	}
+	crsTo, err = validateCRS(crsTo)
+	if err != nil {
+		return nil, err
+	}
+	step := resolveStep(crsFrom, crsTo, &opts)
+	if step == nil {
+		return nil, ErrNoConverter(crsFrom, crsTo)
+	}
+	if format != FormatNDJSON && format != FormatGeoJSONSeq {
+		return nil, &TransformError{
+			Type:    ErrUnsupportedFormat,
+			Message: fmt.Sprintf("不支持的行式格式: %d", format),
+			Details: map[string]interface{}{
+				"format": format,
+			},
+		}
+	}
+
+	gt := &geoJSONTransformer{step: step, opts: opts, to: crsTo}
+	sr := &seqReader{r: bufio.NewReader(r), format: format, line: 1}
+	bw := bufio.NewWriter(w)
+	var lineErrs []*LineError
+
+	for index := 0; ; index++ {
+		record, line, err := sr.next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return lineErrs, err
+		}
+
+		var obj any
+		if err := json.Unmarshal(record, &obj); err != nil {
+			lineErrs = append(lineErrs, &LineError{Line: line, Err: ErrJSONParseFailed(err)})
+			continue
+		}
+		out, err := gt.transformFeature(index, obj)
+		if err != nil {
+			lineErrs = append(lineErrs, &LineError{Line: line, Err: atRoot(err)})
+			continue
+		}
+		gt.rootBBox(out)
+		b, err := json.Marshal(out)
+		if err != nil {
+			lineErrs = append(lineErrs, &LineError{Line: line, Err: err})
+			continue
+		}
+
+		if format == FormatGeoJSONSeq {
+			if err := bw.WriteByte(recordSeparator); err != nil {
+				return lineErrs, err
+			}
+		}
+		if _, err := bw.Write(b); err != nil {
+			return lineErrs, err
+		}
+		if err := bw.WriteByte('\n'); err != nil {
+			return lineErrs, err
+		}
+	}
+	return lineErrs, bw.Flush()
+}
+
+// seqReader 按格式切分记录并跟踪行号
+type seqReader struct {
+	r      *bufio.Reader
+	format SeqFormat
+	// line 下一次读取的起始行号
+	line int
+}
+
+// next 返回下一条非空记录及其起始行号，读完时返回 io.EOF
+func (sr *seqReader) next() ([]byte, int, error) {
+	for {
+		var chunk []byte
+		var err error
+		if sr.format == FormatGeoJSONSeq {
+			chunk, err = sr.r.ReadBytes(recordSeparator)
+			chunk = bytes.TrimSuffix(chunk, []byte{recordSeparator})
+		} else {
+			chunk, err = sr.r.ReadBytes('\n')
+		}
+		if err != nil && err != io.EOF {
+			return nil, 0, err
+		}
+
+		line := sr.line
+		// 跳过记录前的空行，使行号指向记录本身
+		trimmed := bytes.TrimLeft(chunk, " \t\r\n")
+		line += bytes.Count(chunk[:len(chunk)-len(trimmed)], []byte{'\n'})
+		sr.line += bytes.Count(chunk, []byte{'\n'})
+
+		record := bytes.TrimSpace(trimmed)
+		if len(record) > 0 {
+			return record, line, nil
+		}
+		if err == io.EOF {
+			return nil, 0, io.EOF
+		}
+	}
+}
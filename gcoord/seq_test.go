@@ -0,0 +1,74 @@
+package gcoord
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestTransformLinesNDJSON(t *testing.T) {
+	in := strings.Join([]string{
+		`{"type":"Feature","geometry":{"type":"Point","coordinates":[116.397,39.908]},"properties":{"id":1}}`,
+		``,
+		`{"type":"Feature","geometry":{"type":"Point","coordinates":[121.473,31.230]},"properties":{"id":2}}`,
+		`{"type":"Feature","geometry":`,
+		`{"type":"Point","coordinates":[113.264,23.129]}`,
+	}, "\n")
+
+	var out bytes.Buffer
+	lineErrs, err := TransformLines(strings.NewReader(in), &out, WGS84, GCJ02, FormatNDJSON)
+	if err != nil {
+		t.Fatalf("transform lines error: %v", err)
+	}
+	if len(lineErrs) != 1 || lineErrs[0].Line != 4 {
+		t.Fatalf("expect one error at line 4, got %v", lineErrs)
+	}
+	if GetErrorType(lineErrs[0].Err) != ErrInvalidInput {
+		t.Fatalf("expect ErrInvalidInput, got %v", lineErrs[0].Err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expect 3 output records, got %d: %q", len(lines), out.String())
+	}
+	var first map[string]any
+	if err := json.Unmarshal([]byte(lines[0]), &first); err != nil {
+		t.Fatalf("invalid output line: %v", err)
+	}
+	coords := first["geometry"].(map[string]any)["coordinates"].([]any)
+	want := WGS84ToGCJ02(Position{116.397, 39.908})
+	if !approxPos(Position{coords[0].(float64), coords[1].(float64)}, want, 1e-12) {
+		t.Fatalf("record not transformed: got %v want %v", coords, want)
+	}
+}
+
+func TestTransformLinesGeoJSONSeq(t *testing.T) {
+	rs := string(rune(recordSeparator))
+	in := rs + `{"type":"Point","coordinates":[116.397,39.908]}` + "\n" +
+		rs + `{"type":"Point","coordinates":` + "\n" + `[121.473,31.230]}` + "\n" +
+		rs + `{oops}` + "\n"
+
+	var out bytes.Buffer
+	lineErrs, err := TransformLines(strings.NewReader(in), &out, WGS84, BD09, FormatGeoJSONSeq)
+	if err != nil {
+		t.Fatalf("transform lines error: %v", err)
+	}
+	if len(lineErrs) != 1 || lineErrs[0].Line != 4 {
+		t.Fatalf("expect one error at line 4, got %v", lineErrs)
+	}
+
+	records := strings.Split(out.String(), rs)
+	if len(records) != 3 || records[0] != "" {
+		t.Fatalf("expect 2 RS-prefixed records, got %q", out.String())
+	}
+	for _, rec := range records[1:] {
+		if !strings.HasSuffix(rec, "\n") {
+			t.Fatalf("record must end with LF: %q", rec)
+		}
+		var obj map[string]any
+		if err := json.Unmarshal([]byte(rec), &obj); err != nil {
+			t.Fatalf("invalid record %q: %v", rec, err)
+		}
+	}
+}
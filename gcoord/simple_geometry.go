@@ -0,0 +1,174 @@
+package gcoord
+
+import (
+	"strconv"
+	"strings"
+)
+
+// GeometryType OGC 简单要素几何类型，取值与 WKB 类型代码一致
+type GeometryType uint32
+
+const (
+	GeomPoint              GeometryType = 1
+	GeomLineString         GeometryType = 2
+	GeomPolygon            GeometryType = 3
+	GeomMultiPoint         GeometryType = 4
+	GeomMultiLineString    GeometryType = 5
+	GeomMultiPolygon       GeometryType = 6
+	GeomGeometryCollection GeometryType = 7
+	GeomPolyhedralSurface  GeometryType = 15
+	GeomTIN                GeometryType = 16
+	GeomTriangle           GeometryType = 17
+)
+
+// geometryTypeNames 几何类型的 WKT 名称
+var geometryTypeNames = map[GeometryType]string{
+	GeomPoint:              "POINT",
+	GeomLineString:         "LINESTRING",
+	GeomPolygon:            "POLYGON",
+	GeomMultiPoint:         "MULTIPOINT",
+	GeomMultiLineString:    "MULTILINESTRING",
+	GeomMultiPolygon:       "MULTIPOLYGON",
+	GeomGeometryCollection: "GEOMETRYCOLLECTION",
+	GeomPolyhedralSurface:  "POLYHEDRALSURFACE",
+	GeomTIN:                "TIN",
+	GeomTriangle:           "TRIANGLE",
+}
+
+func (t GeometryType) String() string {
+	if name, ok := geometryTypeNames[t]; ok {
+		return name
+	}
+	return "GEOMETRY(" + strconv.Itoa(int(t)) + ")"
+}
+
+// SimpleGeometry OGC 简单要素几何，用于 WKT 与 WKB/EWKB 的解析与输出。
+//
+// 每个坐标为 [x, y]、[x, y, z]、[x, y, m] 或 [x, y, z, m]，由 HasZ、HasM 决定。
+// 不同类型使用的字段：
+//   - Point、LineString：Points，空 Point 的 Points 长度为 0
+//   - Polygon、Triangle：Rings
+//   - MultiPoint、MultiLineString、MultiPolygon、GeometryCollection、
+//     PolyhedralSurface、TIN：Geometries
+type SimpleGeometry struct {
+	Type GeometryType
+	HasZ bool
+	HasM bool
+	// SRID 空间参考 ID，来自 EWKT 的 "SRID=n;" 前缀或 EWKB，0 表示未指定
+	SRID int
+
+	Points     [][]float64
+	Rings      [][][]float64
+	Geometries []*SimpleGeometry
+
+	// 解析 WKB 时记录的格式，输出时保持一致
+	bigEndian bool
+	extended  bool
+}
+
+// IsEmpty 检查几何是否为空
+func (g *SimpleGeometry) IsEmpty() bool {
+	switch g.Type {
+	case GeomPoint, GeomLineString:
+		return len(g.Points) == 0
+	case GeomPolygon, GeomTriangle:
+		return len(g.Rings) == 0
+	default:
+		return len(g.Geometries) == 0
+	}
+}
+
+// dim 返回每个坐标的维数
+func (g *SimpleGeometry) dim() int {
+	n := 2
+	if g.HasZ {
+		n++
+	}
+	if g.HasM {
+		n++
+	}
+	return n
+}
+
+// eachPoint 遍历几何中的所有坐标
+func (g *SimpleGeometry) eachPoint(fn func(pt []float64)) {
+	for _, pt := range g.Points {
+		fn(pt)
+	}
+	for _, ring := range g.Rings {
+		for _, pt := range ring {
+			fn(pt)
+		}
+	}
+	for _, part := range g.Geometries {
+		part.eachPoint(fn)
+	}
+}
+
+// transform 原地转换所有坐标的前两维，Z、M 保持不变，出错时停止
+func (g *SimpleGeometry) transform(point func(x, y float64) (float64, float64, error)) error {
+	var err error
+	g.eachPoint(func(pt []float64) {
+		if err != nil || len(pt) < 2 {
+			return
+		}
+		pt[0], pt[1], err = point(pt[0], pt[1])
+	})
+	return err
+}
+
+// dropExtraDims 丢弃所有坐标的 Z、M 维度
+func (g *SimpleGeometry) dropExtraDims() {
+	g.HasZ, g.HasM = false, false
+	for i, pt := range g.Points {
+		g.Points[i] = pt[:min(len(pt), 2)]
+	}
+	for _, ring := range g.Rings {
+		for i, pt := range ring {
+			ring[i] = pt[:min(len(pt), 2)]
+		}
+	}
+	for _, part := range g.Geometries {
+		part.dropExtraDims()
+	}
+}
+
+// transformGeometry 转换几何，DropExtraDims 时丢弃 Z、M，已指定 SRID 时更新为目标坐标系的 EPSG 代码，
+// 目标坐标系没有 EPSG 代码时清除 SRID
+func transformGeometry(g *SimpleGeometry, gt *geoJSONTransformer, crsTo CRSTypes) error {
+	if err := g.transform(gt.point); err != nil {
+		return err
+	}
+	if gt.opts.DropExtraDims {
+		g.dropExtraDims()
+	}
+	if g.SRID != 0 {
+		g.SRID = epsgCode(crsTo)
+	}
+	return nil
+}
+
+// looksLikeWKT 粗略判断字符串是否为 WKT/EWKT，以 SRID= 或几何类型关键字开头，
+// 其他以字母开头的字符串（如格式错误的 JSON）不视为 WKT
+func looksLikeWKT(s string) bool {
+	if len(s) > 5 && strings.EqualFold(s[:5], "SRID=") {
+		return true
+	}
+	p := &wktParser{s: s}
+	_, _, ok := parseWKTType(strings.ToUpper(p.word()))
+	return ok
+}
+
+// looksLikeWKBHex 粗略判断字符串是否为十六进制 WKB，以字节序标记 00 或 01 开头
+func looksLikeWKBHex(s string) bool {
+	if len(s) < 10 || len(s)%2 != 0 || (!strings.HasPrefix(s, "00") && !strings.HasPrefix(s, "01")) {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if !(c >= '0' && c <= '9') && !(c >= 'a' && c <= 'f') && !(c >= 'A' && c <= 'F') {
+			return false
+		}
+	}
+	return true
+}
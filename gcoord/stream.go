@@ -0,0 +1,385 @@
+package gcoord
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// TransformStream 从 r 流式读取 GeoJSON，转换后写入 w。
+//
+// 顶层为对象时逐个成员处理：FeatureCollection 的 features 逐个读取、转换并立即写出，
+// 内存占用只与单个 feature 的大小有关，适合转换超出内存的大文件；
+// 与 Transform 一致，只转换与 type 对应的成员，其余成员原样写出。
+// type 位于 features 等成员之后时，这些成员需暂存到读到 type 为止，无法流式处理。
+// 顶层不是对象时整体读取后转换。
+//
+// 示例：
+//
+//	in, _ := os.Open("input.geojson")
+//	out, _ := os.Create("output.geojson")
+//	err := TransformStream(in, out, WGS84, GCJ02)
+func TransformStream(r io.Reader, w io.Writer, crsFrom, crsTo CRSTypes) error {
+	return TransformStreamWithOptions(r, w, crsFrom, crsTo, TransformOptions{})
+}
+
+// TransformStreamWithOptions 与 TransformStream 相同，但可通过 opts 控制转换行为
+func TransformStreamWithOptions(r io.Reader, w io.Writer, crsFrom, crsTo CRSTypes, opts TransformOptions) error {
+	crsFrom, err := validateCRS(crsFrom)
+	if err != nil {
+		return err
+	}
+	crsTo, err = validateCRS(crsTo)
+	if err != nil {
+		return err
+	}
+	step := resolveStep(crsFrom, crsTo, &opts)
+	if step == nil {
+		return ErrNoConverter(crsFrom, crsTo)
+	}
+
+	br := bufio.NewReader(r)
+	bw := bufio.NewWriter(w)
+	st := &streamTransformer{
+		gt:  &geoJSONTransformer{step: step, opts: opts, to: crsTo},
+		dec: json.NewDecoder(br),
+		w:   bw,
+	}
+
+	first, err := peekNonSpace(br)
+	if err != nil {
+		return ErrJSONParseFailed(err)
+	}
+	if first == '{' {
+		err = st.object()
+	} else {
+		err = st.value()
+	}
+	if err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+// streamTransformer 基于 json.Decoder 的流式 GeoJSON 转换
+type streamTransformer struct {
+	gt    *geoJSONTransformer
+	dec   *json.Decoder
+	w     *bufio.Writer
+	index int
+	// bounds 已写出坐标的外包矩形，用于顶层 bbox
+	bounds  bounds
+	hasBBox bool
+	// typ 顶层对象的 type 成员，typeKnown 为 false 时尚未读到
+	typ       any
+	typeKnown bool
+}
+
+// pendingMember 读到 type 之前暂存的顶层成员
+type pendingMember struct {
+	key string
+	raw json.RawMessage
+}
+
+// value 整体读取一个 JSON 值，转换后写出
+func (st *streamTransformer) value() error {
+	var obj any
+	if err := st.dec.Decode(&obj); err != nil {
+		return ErrJSONParseFailed(err)
+	}
+	out, err := st.gt.document(obj)
+	if err != nil {
+		return err
+	}
+	return st.encode(out)
+}
+
+// object 逐个成员处理顶层对象
+func (st *streamTransformer) object() error {
+	if err := st.expectDelim('{'); err != nil {
+		return err
+	}
+	if err := st.w.WriteByte('{'); err != nil {
+		return err
+	}
+
+	// 是否转换 coordinates 等成员取决于 type，与 geoJSONTransformer.transform 一致。
+	// 读到 type 之前遇到这些成员时暂存其后的所有成员，读到 type 或对象结束后按原顺序写出
+	n := 0
+	var pending []pendingMember
+	for st.dec.More() {
+		tok, err := st.dec.Token()
+		if err != nil {
+			return ErrJSONParseFailed(err)
+		}
+		key, ok := tok.(string)
+		if !ok {
+			return ErrJSONParseFailed(fmt.Errorf("期望对象键，得到 %v", tok))
+		}
+		if key == "type" && !st.typeKnown {
+			var raw json.RawMessage
+			if err := st.dec.Decode(&raw); err != nil {
+				return ErrJSONParseFailed(err)
+			}
+			_ = json.Unmarshal(raw, &st.typ)
+			st.typeKnown = true
+			pending = append(pending, pendingMember{key, raw})
+			if err := st.flush(&n, pending); err != nil {
+				return err
+			}
+			pending = nil
+			continue
+		}
+		if pending != nil || (!st.typeKnown && typedMember(key)) {
+			var raw json.RawMessage
+			if err := st.dec.Decode(&raw); err != nil {
+				return ErrJSONParseFailed(err)
+			}
+			pending = append(pending, pendingMember{key, raw})
+			continue
+		}
+		if err := st.memberAt(&n, key); err != nil {
+			return err
+		}
+	}
+
+	if err := st.expectDelim('}'); err != nil {
+		return err
+	}
+	// 没有 type 成员时暂存的成员原样写出
+	st.typeKnown = true
+	if err := st.flush(&n, pending); err != nil {
+		return err
+	}
+	if st.gt.opts.AddBBox && !st.hasBBox && st.bounds.ok {
+		if n > 0 {
+			if err := st.w.WriteByte(','); err != nil {
+				return err
+			}
+		}
+		if _, err := st.w.WriteString(`"bbox":`); err != nil {
+			return err
+		}
+		if err := st.encode([]any{st.bounds.minX, st.bounds.minY, st.bounds.maxX, st.bounds.maxY}); err != nil {
+			return err
+		}
+	}
+	return st.w.WriteByte('}')
+}
+
+// memberAt 写出第 n 个成员的键，转换并写出其值
+func (st *streamTransformer) memberAt(n *int, key string) error {
+	if *n > 0 {
+		if err := st.w.WriteByte(','); err != nil {
+			return err
+		}
+	}
+	*n++
+	if err := st.encode(key); err != nil {
+		return err
+	}
+	if err := st.w.WriteByte(':'); err != nil {
+		return err
+	}
+	return st.member(key)
+}
+
+// flush 按顺序转换并写出暂存的成员
+func (st *streamTransformer) flush(n *int, pending []pendingMember) error {
+	dec := st.dec
+	defer func() { st.dec = dec }()
+	for _, m := range pending {
+		st.dec = json.NewDecoder(bytes.NewReader(m.raw))
+		if err := st.memberAt(n, m.key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// typedMember 报告成员是否需要根据 type 决定如何处理
+func typedMember(key string) bool {
+	switch key {
+	case "bbox", "crs", "features", "coordinates", "geometry", "geometries":
+		return true
+	}
+	return false
+}
+
+// handles 报告是否按 type 转换成员 key，与 geoJSONTransformer.transform 一致，没有 type 时均原样写出
+func (st *streamTransformer) handles(key string) bool {
+	typ, ok := st.typ.(string)
+	if !ok {
+		return false
+	}
+	switch key {
+	case "bbox", "crs":
+		return true
+	case "features":
+		return typ == "FeatureCollection"
+	case "geometry":
+		return typ == "Feature"
+	case "geometries":
+		return typ == "GeometryCollection"
+	case "coordinates":
+		return typ != "FeatureCollection" && typ != "Feature" && typ != "GeometryCollection"
+	}
+	return false
+}
+
+// member 转换并写出顶层对象的一个成员值，调用前须已读到 type，见 handles。
+// 顶层 bbox 位于坐标之后时按已写出的坐标重新计算，否则转换其角点
+func (st *streamTransformer) member(key string) error {
+	if !st.handles(key) {
+		return st.raw()
+	}
+	switch key {
+	case "bbox":
+		var v any
+		if err := st.dec.Decode(&v); err != nil {
+			return ErrJSONParseFailed(err)
+		}
+		st.hasBBox = true
+		if bbox, ok := v.([]any); ok {
+			if st.bounds.ok {
+				m := map[string]any{"bbox": bbox}
+				st.bounds.apply(m)
+				v = m["bbox"]
+			} else {
+				out, err := st.gt.transformBBox(bbox)
+				if err != nil {
+					return atRoot(err)
+				}
+				v = out
+			}
+		}
+		return st.encode(v)
+	case "crs":
+		var raw json.RawMessage
+		if err := st.dec.Decode(&raw); err != nil {
+			return ErrJSONParseFailed(err)
+		}
+		return st.encode(crsMember(st.gt.to))
+	case "features":
+		return st.features()
+	case "coordinates":
+		var v any
+		if err := st.dec.Decode(&v); err != nil {
+			return ErrJSONParseFailed(err)
+		}
+		out, err := st.gt.coords(v)
+		if err != nil {
+			return atRoot(withPath(err, ".coordinates"))
+		}
+		st.bounds.coords(out)
+		return st.encode(out)
+	case "geometry", "geometries":
+		var v any
+		if err := st.dec.Decode(&v); err != nil {
+			return ErrJSONParseFailed(err)
+		}
+		out, err := st.gt.transform(v)
+		if err != nil {
+			return atRoot(withPath(err, "."+key))
+		}
+		st.bounds.extent(out)
+		return st.encode(out)
+	default:
+		return st.raw()
+	}
+}
+
+// raw 原样写出下一个成员值
+func (st *streamTransformer) raw() error {
+	var raw json.RawMessage
+	if err := st.dec.Decode(&raw); err != nil {
+		return ErrJSONParseFailed(err)
+	}
+	_, err := st.w.Write(raw)
+	return err
+}
+
+// features 逐个读取、转换并写出 features 数组中的元素
+func (st *streamTransformer) features() error {
+	tok, err := st.dec.Token()
+	if err != nil {
+		return ErrJSONParseFailed(err)
+	}
+	if tok == nil {
+		_, err := st.w.WriteString("null")
+		return err
+	}
+	if d, ok := tok.(json.Delim); !ok || d != '[' {
+		return ErrJSONParseFailed(fmt.Errorf("features 必须为数组，得到 %v", tok))
+	}
+	if err := st.w.WriteByte('['); err != nil {
+		return err
+	}
+
+	for ; st.dec.More(); st.index++ {
+		var feature any
+		if err := st.dec.Decode(&feature); err != nil {
+			return ErrJSONParseFailed(err)
+		}
+		out, err := st.gt.transformFeature(st.index, feature)
+		if err != nil {
+			return atRoot(withPath(err, fmt.Sprintf(".features[%d]", st.index)))
+		}
+		st.bounds.extent(out)
+		if st.index > 0 {
+			if err := st.w.WriteByte(','); err != nil {
+				return err
+			}
+		}
+		if err := st.encode(out); err != nil {
+			return err
+		}
+	}
+
+	if err := st.expectDelim(']'); err != nil {
+		return err
+	}
+	return st.w.WriteByte(']')
+}
+
+// expectDelim 读取下一个 token 并检查是否为指定分隔符
+func (st *streamTransformer) expectDelim(want json.Delim) error {
+	tok, err := st.dec.Token()
+	if err != nil {
+		return ErrJSONParseFailed(err)
+	}
+	if d, ok := tok.(json.Delim); !ok || d != want {
+		return ErrJSONParseFailed(fmt.Errorf("期望 %v，得到 %v", want, tok))
+	}
+	return nil
+}
+
+// encode 以紧凑格式写出 v
+func (st *streamTransformer) encode(v any) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	_, err = st.w.Write(b)
+	return err
+}
+
+// peekNonSpace 跳过空白并返回下一个字节，不消费该字节
+func peekNonSpace(br *bufio.Reader) (byte, error) {
+	for {
+		b, err := br.Peek(1)
+		if err != nil {
+			return 0, err
+		}
+		switch b[0] {
+		case ' ', '\t', '\r', '\n':
+			if _, err := br.ReadByte(); err != nil {
+				return 0, err
+			}
+		default:
+			return b[0], nil
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package gcoord
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestTransformStreamFeatureCollection(t *testing.T) {
+	fc := makeFeatureCollection(100)
+	fc["name"] = "fleet"
+	src, _ := json.Marshal(fc)
+
+	var out bytes.Buffer
+	if err := TransformStream(bytes.NewReader(src), &out, WGS84, GCJ02); err != nil {
+		t.Fatalf("stream transform error: %v", err)
+	}
+
+	want, err := Transform(string(src), WGS84, GCJ02)
+	if err != nil {
+		t.Fatalf("transform error: %v", err)
+	}
+	var got, expected map[string]any
+	if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+		t.Fatalf("stream output is not valid JSON: %v", err)
+	}
+	_ = json.Unmarshal([]byte(want), &expected)
+
+	gb, _ := json.Marshal(got)
+	eb, _ := json.Marshal(expected)
+	if !bytes.Equal(gb, eb) {
+		t.Fatalf("stream output mismatch:\n got %s\nwant %s", gb, eb)
+	}
+}
+
+func TestTransformStreamGeometryAndFeature(t *testing.T) {
+	cases := []string{
+		`{"type":"Point","coordinates":[116.397,39.908]}`,
+		` {"type":"Feature","properties":{"coordinates":[1,2]},"geometry":{"type":"LineString","coordinates":[[116.39,39.90],[116.40,39.91]]}}`,
+		`{"features":[],"type":"FeatureCollection"}`,
+		`[{"type":"Point","coordinates":[116.397,39.908]}]`,
+		// 只转换与 type 对应的成员，type 位于其后时同样处理
+		`{"type":"Feature","coordinates":[116.39,39.90],"geometry":{"type":"Point","coordinates":[116.397,39.908]}}`,
+		`{"geometry":{"type":"Point","coordinates":[116.39,39.90]},"features":[],"type":"FeatureCollection"}`,
+		`{"coordinates":[116.397,39.908],"name":"p","type":"Point","bbox":[116.397,39.908,116.397,39.908]}`,
+		`{"coordinates":[116.397,39.908],"geometry":{"type":"Point","coordinates":[116.39,39.90]}}`,
+	}
+	for _, in := range cases {
+		var out bytes.Buffer
+		if err := TransformStream(strings.NewReader(in), &out, WGS84, BD09); err != nil {
+			t.Fatalf("stream transform %s error: %v", in, err)
+		}
+		want, err := Transform(in, WGS84, BD09)
+		if err != nil {
+			t.Fatalf("transform error: %v", err)
+		}
+		var got, expected any
+		if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+			t.Fatalf("invalid output %s: %v", out.String(), err)
+		}
+		_ = json.Unmarshal([]byte(want), &expected)
+		gb, _ := json.Marshal(got)
+		eb, _ := json.Marshal(expected)
+		if !bytes.Equal(gb, eb) {
+			t.Fatalf("stream output mismatch:\n got %s\nwant %s", gb, eb)
+		}
+	}
+}
+
+func TestTransformStreamInvalidJSON(t *testing.T) {
+	var out bytes.Buffer
+	err := TransformStream(strings.NewReader(`{"type":"FeatureCollection","features":[{]}`), &out, WGS84, GCJ02)
+	if GetErrorType(err) != ErrInvalidInput {
+		t.Fatalf("expect ErrInvalidInput, got %v", err)
+	}
+}
@@ -0,0 +1,319 @@
+package gcoord
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// TransformStruct 按 gcoord 结构体标签原地转换 v 中的坐标字段，v 必须是非 nil 指针或切片。
+//
+// 支持的标签：
+//   - `gcoord:"lon"`、`gcoord:"lat"`: 经度（x）与纬度（y）字段，类型为浮点数或浮点数指针
+//   - `gcoord:"lon,name"`、`gcoord:"lat,name"`: 同一结构体中有多组坐标时按 name 配对
+//   - `gcoord:"crs"`: 字符串字段，非空时作为该结构体（含嵌套结构体）的源坐标系并覆盖 crsFrom，
+//     用于处理坐标系不同的记录。默认只读取，设置 TransformOptions.UpdateCRSField 时转换后更新为 crsTo
+//
+// 嵌套结构体、指针、切片与数组会递归处理，同一指针只转换一次，未导出的字段（内嵌结构体除外）被忽略。
+// 出错时停止，错误的 Details["path"] 为字段路径，如 "$.Stops[2].Lng"。
+//
+// 示例：
+//
+//	type Shop struct {
+//		Name string
+//		Lng  float64 `gcoord:"lon"`
+//		Lat  float64 `gcoord:"lat"`
+//	}
+//	err := TransformStruct(&shops, BD09, GCJ02)
+func TransformStruct(v any, crsFrom, crsTo CRSTypes) error {
+	return TransformStructWithOptions(v, crsFrom, crsTo, TransformOptions{})
+}
+
+// TransformStructWithOptions 与 TransformStruct 相同，但可通过 opts 控制转换行为。
+// crsFrom 可以为空，此时每条记录都必须有非空的 crs 字段
+func TransformStructWithOptions(v any, crsFrom, crsTo CRSTypes, opts TransformOptions) error {
+	var err error
+	if crsFrom != "" {
+		if crsFrom, err = validateCRS(crsFrom); err != nil {
+			return err
+		}
+	}
+	if crsTo, err = validateCRS(crsTo); err != nil {
+		return err
+	}
+
+	rv := reflect.ValueOf(v)
+	if !rv.IsValid() || (rv.Kind() != reflect.Pointer && rv.Kind() != reflect.Slice) || rv.IsNil() {
+		return &TransformError{
+			Type:    ErrInvalidInput,
+			Message: fmt.Sprintf("TransformStruct 需要非 nil 的指针或切片，得到 %T", v),
+			Details: map[string]interface{}{
+				"type": fmt.Sprintf("%T", v),
+			},
+		}
+	}
+
+	w := &structWalker{
+		gt:      geoJSONTransformer{opts: opts, to: crsTo},
+		steps:   map[CRSTypes]pointStep{},
+		visited: map[visitKey]bool{},
+	}
+	return atRoot(w.value(rv, crsFrom))
+}
+
+// structWalker 遍历结构体并转换带标签的字段
+type structWalker struct {
+	gt geoJSONTransformer
+	// steps 按源坐标系缓存的转换步骤
+	steps map[CRSTypes]pointStep
+	// visited 已处理的指针，避免重复转换与循环引用
+	visited map[visitKey]bool
+}
+
+// visitKey 结构体与其第一个字段地址相同，因此需要同时记录类型
+type visitKey struct {
+	ptr uintptr
+	typ reflect.Type
+}
+
+func (w *structWalker) value(v reflect.Value, from CRSTypes) error {
+	switch v.Kind() {
+	case reflect.Pointer:
+		key := visitKey{v.Pointer(), v.Type()}
+		if v.IsNil() || w.visited[key] {
+			return nil
+		}
+		w.visited[key] = true
+		return w.value(v.Elem(), from)
+	case reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		// 接口中的非指针值不可修改，只处理指针
+		if e := v.Elem(); e.Kind() == reflect.Pointer {
+			return w.value(e, from)
+		}
+		return nil
+	case reflect.Struct:
+		return w.structValue(v, from)
+	case reflect.Slice, reflect.Array:
+		if !hasCoordFields(v.Type().Elem()) {
+			return nil
+		}
+		for i := 0; i < v.Len(); i++ {
+			if err := w.value(v.Index(i), from); err != nil {
+				return withPath(err, fmt.Sprintf("[%d]", i))
+			}
+		}
+	}
+	return nil
+}
+
+func (w *structWalker) structValue(v reflect.Value, from CRSTypes) error {
+	info, err := structInfoOf(v.Type())
+	if err != nil {
+		return err
+	}
+
+	if info.crs >= 0 {
+		f := v.Field(info.crs)
+		if s := f.String(); s != "" {
+			crs, err := validateCRS(CRSTypes(s))
+			if err != nil {
+				return withPath(err, "."+v.Type().Field(info.crs).Name)
+			}
+			from = crs
+		}
+	}
+
+	if len(info.pairs) > 0 {
+		if from == "" {
+			return &TransformError{
+				Type:    ErrInvalidCRS,
+				Message: fmt.Sprintf("%s 没有源坐标系：crsFrom 为空且 crs 字段为空", v.Type()),
+				Details: map[string]interface{}{
+					"type": v.Type().String(),
+				},
+			}
+		}
+		step, err := w.step(from)
+		if err != nil {
+			return err
+		}
+		w.gt.step = step
+		for _, p := range info.pairs {
+			if err := w.pair(v.Field(p.lon), v.Field(p.lat)); err != nil {
+				return withPath(err, "."+v.Type().Field(p.lon).Name)
+			}
+		}
+	}
+
+	for _, i := range info.nested {
+		if err := w.value(v.Field(i), from); err != nil {
+			return withPath(err, "."+v.Type().Field(i).Name)
+		}
+	}
+	if w.gt.opts.UpdateCRSField && info.crs >= 0 && from != "" && v.Field(info.crs).CanSet() {
+		v.Field(info.crs).SetString(string(w.gt.to))
+	}
+	return nil
+}
+
+// pair 转换一组经纬度字段，指针字段为 nil 时跳过
+func (w *structWalker) pair(lon, lat reflect.Value) error {
+	if lon.Kind() == reflect.Pointer {
+		if lon.IsNil() || lat.IsNil() {
+			return nil
+		}
+		lon, lat = lon.Elem(), lat.Elem()
+	}
+	if !lon.CanSet() || !lat.CanSet() {
+		return nil
+	}
+	x, y, err := w.gt.point(lon.Float(), lat.Float())
+	if err != nil {
+		return err
+	}
+	lon.SetFloat(x)
+	lat.SetFloat(y)
+	return nil
+}
+
+func (w *structWalker) step(from CRSTypes) (pointStep, error) {
+	if step, ok := w.steps[from]; ok {
+		return step, nil
+	}
+	step := resolveStep(from, w.gt.to, &w.gt.opts)
+	if step == nil {
+		return nil, ErrNoConverter(from, w.gt.to)
+	}
+	w.steps[from] = step
+	return step, nil
+}
+
+// structInfo 结构体中带标签字段的序号
+type structInfo struct {
+	// crs 源坐标系字段，没有时为 -1
+	crs   int
+	pairs []coordPair
+	// nested 可能包含坐标字段的结构体、指针、切片等字段
+	nested []int
+}
+
+type coordPair struct {
+	lon, lat int
+}
+
+var structInfoCache sync.Map // reflect.Type -> *structInfo 或 error
+
+// structInfoOf 解析并缓存结构体的标签信息，标签用于不支持的字段类型或经纬度不成对时返回错误
+func structInfoOf(t reflect.Type) (*structInfo, error) {
+	if cached, ok := structInfoCache.Load(t); ok {
+		if err, ok := cached.(error); ok {
+			return nil, err
+		}
+		return cached.(*structInfo), nil
+	}
+	info, err := parseStructInfo(t)
+	if err != nil {
+		structInfoCache.Store(t, err)
+		return nil, err
+	}
+	structInfoCache.Store(t, info)
+	return info, nil
+}
+
+func parseStructInfo(t reflect.Type) (*structInfo, error) {
+	info := &structInfo{crs: -1}
+	lons, lats := map[string]int{}, map[string]int{}
+	var groups []string
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		// 未导出的内嵌结构体与 encoding/json 一样提升其导出字段
+		if !f.IsExported() && !(f.Anonymous && hasCoordFields(f.Type)) {
+			continue
+		}
+		tag, ok := f.Tag.Lookup("gcoord")
+		if !ok || tag == "-" {
+			if hasCoordFields(f.Type) {
+				info.nested = append(info.nested, i)
+			}
+			continue
+		}
+
+		role, group, _ := strings.Cut(tag, ",")
+		switch role {
+		case "crs":
+			if f.Type.Kind() != reflect.String {
+				return nil, errStructTag(t, f, "crs 字段必须为字符串类型")
+			}
+			info.crs = i
+			continue
+		case "lon", "lat":
+		default:
+			return nil, errStructTag(t, f, fmt.Sprintf("未知的标签 %q", tag))
+		}
+
+		ft := f.Type
+		if ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		if ft.Kind() != reflect.Float64 && ft.Kind() != reflect.Float32 {
+			return nil, errStructTag(t, f, "坐标字段必须为浮点数或浮点数指针")
+		}
+		fields := lons
+		if role == "lat" {
+			fields = lats
+		}
+		if _, dup := fields[group]; dup {
+			return nil, errStructTag(t, f, fmt.Sprintf("重复的 %s 字段", tag))
+		}
+		if _, seen := lons[group]; !seen {
+			if _, seen := lats[group]; !seen {
+				groups = append(groups, group)
+			}
+		}
+		fields[group] = i
+	}
+
+	for _, g := range groups {
+		lon, okLon := lons[g]
+		lat, okLat := lats[g]
+		if !okLon || !okLat {
+			field := t.Field(max(lon, lat))
+			return nil, errStructTag(t, field, "lon 与 lat 字段必须成对出现")
+		}
+		if t.Field(lon).Type != t.Field(lat).Type {
+			return nil, errStructTag(t, t.Field(lat), "同一组的 lon 与 lat 字段类型必须相同")
+		}
+		info.pairs = append(info.pairs, coordPair{lon, lat})
+	}
+	return info, nil
+}
+
+// hasCoordFields 判断类型是否可能包含带标签的字段：结构体，或元素为结构体的指针、切片、数组、接口
+func hasCoordFields(t reflect.Type) bool {
+	for {
+		switch t.Kind() {
+		case reflect.Struct, reflect.Interface:
+			return true
+		case reflect.Pointer, reflect.Slice, reflect.Array:
+			t = t.Elem()
+		default:
+			return false
+		}
+	}
+}
+
+func errStructTag(t reflect.Type, f reflect.StructField, msg string) *TransformError {
+	return &TransformError{
+		Type:    ErrInvalidInput,
+		Message: fmt.Sprintf("%s.%s 的 gcoord 标签无效: %s", t, f.Name, msg),
+		Details: map[string]interface{}{
+			"type":  t.String(),
+			"field": f.Name,
+		},
+	}
+}
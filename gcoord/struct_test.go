@@ -0,0 +1,150 @@
+package gcoord
+
+import (
+	"errors"
+	"testing"
+)
+
+type structStop struct {
+	Name string
+	Lng  float64 `gcoord:"lon"`
+	Lat  float64 `gcoord:"lat"`
+}
+
+type structRoute struct {
+	CRS      string  `gcoord:"crs"`
+	FromLng  float64 `gcoord:"lon,from"`
+	FromLat  float64 `gcoord:"lat,from"`
+	ToLng    float32 `gcoord:"lon,to"`
+	ToLat    float32 `gcoord:"lat,to"`
+	Stops    []structStop
+	Depot    *structStop
+	Optional *float64 `gcoord:"lon,opt"`
+	OptLat   *float64 `gcoord:"lat,opt"`
+	Tags     map[string]string
+}
+
+func TestTransformStruct(t *testing.T) {
+	src := Position{116.397, 39.908}
+	want, _ := Transform(src, WGS84, GCJ02)
+
+	depot := &structStop{Lng: src[0], Lat: src[1]}
+	r := structRoute{
+		FromLng: src[0], FromLat: src[1],
+		ToLng: float32(src[0]), ToLat: float32(src[1]),
+		Stops: []structStop{{Lng: src[0], Lat: src[1]}, {Lng: src[0], Lat: src[1]}},
+		Depot: depot,
+	}
+	if err := TransformStruct(&r, WGS84, GCJ02); err != nil {
+		t.Fatalf("TransformStruct: %v", err)
+	}
+	for name, got := range map[string]Position{
+		"from":     {r.FromLng, r.FromLat},
+		"stops[1]": {r.Stops[1].Lng, r.Stops[1].Lat},
+		"depot":    {r.Depot.Lng, r.Depot.Lat},
+	} {
+		if !approxPos(got, want, 1e-9) {
+			t.Errorf("%s: got %v want %v", name, got, want)
+		}
+	}
+	if !approxPos(Position{float64(r.ToLng), float64(r.ToLat)}, want, 1e-4) {
+		t.Errorf("float32 pair: got %v,%v want %v", r.ToLng, r.ToLat, want)
+	}
+	if r.CRS != "" {
+		t.Errorf("crs field should be left unchanged, got %q", r.CRS)
+	}
+
+	// 共享指针只转换一次
+	shared := &structStop{Lng: src[0], Lat: src[1]}
+	list := []*structStop{shared, shared}
+	if err := TransformStruct(list, WGS84, GCJ02); err != nil {
+		t.Fatalf("TransformStruct slice: %v", err)
+	}
+	if !approxPos(Position{shared.Lng, shared.Lat}, want, 1e-9) {
+		t.Errorf("shared pointer converted twice: %v", *shared)
+	}
+}
+
+func TestTransformStructMixedCRS(t *testing.T) {
+	src := Position{116.397, 39.908}
+	gcj, _ := Transform(src, WGS84, GCJ02)
+	bd, _ := Transform(src, WGS84, BD09)
+
+	records := []structRoute{
+		{CRS: "WGS84", FromLng: src[0], FromLat: src[1], Stops: []structStop{{Lng: src[0], Lat: src[1]}}},
+		{CRS: "BD09", FromLng: bd[0], FromLat: bd[1]},
+		{FromLng: src[0], FromLat: src[1]},
+	}
+	if err := TransformStruct(records, WGS84, GCJ02); err != nil {
+		t.Fatalf("TransformStruct: %v", err)
+	}
+	for i, r := range records[:2] {
+		if !approxPos(Position{r.FromLng, r.FromLat}, gcj, 1e-6) {
+			t.Errorf("record %d: got %v,%v want %v", i, r.FromLng, r.FromLat, gcj)
+		}
+	}
+	// 默认不修改 crs 字段
+	if records[0].CRS != "WGS84" || records[1].CRS != "BD09" || records[2].CRS != "" {
+		t.Errorf("crs fields changed: %q, %q, %q", records[0].CRS, records[1].CRS, records[2].CRS)
+	}
+	// 嵌套结构体继承外层 crs 字段
+	if s := records[0].Stops[0]; !approxPos(Position{s.Lng, s.Lat}, gcj, 1e-9) {
+		t.Errorf("nested stop: got %v", s)
+	}
+
+	// crsFrom 为空时 crs 字段必须非空
+	err := TransformStruct(&[]structRoute{{CRS: "WGS84"}, {}}, "", GCJ02)
+	var te *TransformError
+	if !errors.As(err, &te) || te.Type != ErrInvalidCRS {
+		t.Fatalf("expected ErrInvalidCRS, got %v", err)
+	}
+	if te.Details["path"] != "$[1]" {
+		t.Errorf("path = %v, want $[1]", te.Details["path"])
+	}
+
+	// UpdateCRSField 时更新 crs 字段，重复调用不会再次转换
+	update := TransformOptions{UpdateCRSField: true}
+	labelled := []structRoute{{CRS: "BD09", FromLng: bd[0], FromLat: bd[1]}, {FromLng: src[0], FromLat: src[1]}}
+	for range 2 {
+		if err := TransformStructWithOptions(labelled, WGS84, GCJ02, update); err != nil {
+			t.Fatalf("TransformStructWithOptions: %v", err)
+		}
+	}
+	for i, r := range labelled {
+		if r.CRS != string(GCJ02) || !approxPos(Position{r.FromLng, r.FromLat}, gcj, 1e-6) {
+			t.Errorf("record %d: crs %q, got %v,%v want %v", i, r.CRS, r.FromLng, r.FromLat, gcj)
+		}
+	}
+}
+
+func TestTransformStructErrors(t *testing.T) {
+	var te *TransformError
+
+	if err := TransformStruct(structStop{}, WGS84, GCJ02); !errors.As(err, &te) || te.Type != ErrInvalidInput {
+		t.Errorf("non-pointer: expected ErrInvalidInput, got %v", err)
+	}
+
+	type unpaired struct {
+		Lng float64 `gcoord:"lon"`
+	}
+	if err := TransformStruct(&unpaired{}, WGS84, GCJ02); !errors.As(err, &te) || te.Type != ErrInvalidInput {
+		t.Errorf("unpaired: expected ErrInvalidInput, got %v", err)
+	}
+
+	type badType struct {
+		Lng string `gcoord:"lon"`
+		Lat string `gcoord:"lat"`
+	}
+	if err := TransformStruct(&badType{}, WGS84, GCJ02); !errors.As(err, &te) || te.Type != ErrInvalidInput {
+		t.Errorf("non-float: expected ErrInvalidInput, got %v", err)
+	}
+
+	r := structRoute{Stops: []structStop{{Lng: 116, Lat: 39}, {Lng: 116, Lat: 95}}}
+	err := TransformStructWithOptions(&r, WGS84, GCJ02, TransformOptions{Strict: true})
+	if !errors.As(err, &te) || te.Type != ErrInvalidInput {
+		t.Fatalf("strict: expected ErrInvalidInput, got %v", err)
+	}
+	if te.Details["path"] != "$.Stops[1].Lng" {
+		t.Errorf("path = %v, want $.Stops[1].Lng", te.Details["path"])
+	}
+}
@@ -0,0 +1,133 @@
+package gcoord
+
+import "math"
+
+// transverseMercator 横轴墨卡托投影（高斯-克吕格、UTM 等），
+// 使用 Krüger 级数展开至 n^6 阶（Karney 2011），距中央经线 4000 千米内误差小于 1 毫米
+type transverseMercator struct {
+	// e 第一偏心率
+	e float64
+	// ka 缩放后的等量纬度半径 k0·A
+	ka float64
+	// alpha 正算系数，beta 反算系数
+	alpha, beta [6]float64
+	// lon0 中央经线（弧度）
+	lon0 float64
+	// fe、fn 东伪偏移与北伪偏移，y0 原点纬度处的北向坐标
+	fe, fn, y0 float64
+}
+
+// tmMaxNorthing 北向坐标上限，略大于赤道到极点的子午线弧长，用于投影带的有效范围
+const tmMaxNorthing = 10002000
+
+// TransverseMercator 横轴墨卡托（高斯-克吕格）投影参数，角度单位为度，
+// 用于 RegisterTransverseMercator
+type TransverseMercator struct {
+	// CentralMeridian 中央经线
+	CentralMeridian float64
+	// LatitudeOfOrigin 原点纬度
+	LatitudeOfOrigin float64
+	// ScaleFactor 中央经线比例因子，为 0 时取 1
+	ScaleFactor float64
+	// FalseEasting、FalseNorthing 东伪偏移与北伪偏移（米）
+	FalseEasting, FalseNorthing float64
+}
+
+func newTransverseMercator(e Ellipsoid, p TransverseMercator) *transverseMercator {
+	k0 := p.ScaleFactor
+	if k0 == 0 {
+		k0 = 1
+	}
+	n := e.F / (2 - e.F)
+	n2 := n * n
+	n3, n4, n5, n6 := n2*n, n2*n2, n2*n2*n, n2*n2*n2
+
+	tm := &transverseMercator{
+		e:    math.Sqrt(e.F * (2 - e.F)),
+		ka:   k0 * e.A / (1 + n) * (1 + n2/4 + n4/64 + n6/256),
+		lon0: p.CentralMeridian * DegToRad,
+		fe:   p.FalseEasting,
+		fn:   p.FalseNorthing,
+		alpha: [6]float64{
+			n/2 - 2*n2/3 + 5*n3/16 + 41*n4/180 - 127*n5/288 + 7891*n6/37800,
+			13*n2/48 - 3*n3/5 + 557*n4/1440 + 281*n5/630 - 1983433*n6/1935360,
+			61*n3/240 - 103*n4/140 + 15061*n5/26880 + 167603*n6/181440,
+			49561*n4/161280 - 179*n5/168 + 6601661*n6/7257600,
+			34729*n5/80640 - 3418889*n6/1995840,
+			212378941 * n6 / 319334400,
+		},
+		beta: [6]float64{
+			n/2 - 2*n2/3 + 37*n3/96 - n4/360 - 81*n5/512 + 96199*n6/604800,
+			n2/48 + n3/15 - 437*n4/1440 + 46*n5/105 - 1118711*n6/3870720,
+			17*n3/480 - 37*n4/840 - 209*n5/4480 + 5569*n6/90720,
+			4397*n4/161280 - 11*n5/504 - 830251*n6/7257600,
+			4583*n5/161280 - 108847*n6/3991680,
+			20648693 * n6 / 638668800,
+		},
+	}
+	if p.LatitudeOfOrigin != 0 {
+		_, tm.y0 = tm.project(p.LatitudeOfOrigin*DegToRad, 0)
+	}
+	return tm
+}
+
+// forward 经纬度 -> 投影坐标
+func (tm *transverseMercator) forward(lon, lat float64) (float64, float64) {
+	x, y := tm.project(lat*DegToRad, lon*DegToRad-tm.lon0)
+	return tm.fe + x, tm.fn + y - tm.y0
+}
+
+// project 计算相对中央经线的投影坐标，未加伪偏移
+func (tm *transverseMercator) project(phi, lam float64) (float64, float64) {
+	tau := math.Tan(phi)
+	sigma := math.Sinh(tm.e * math.Atanh(tm.e*tau/math.Hypot(1, tau)))
+	taup := tau*math.Hypot(1, sigma) - sigma*math.Hypot(1, tau)
+
+	cosLam := math.Cos(lam)
+	xip := math.Atan2(taup, cosLam)
+	etap := math.Asinh(math.Sin(lam) / math.Hypot(taup, cosLam))
+
+	xi, eta := xip, etap
+	for j, a := range tm.alpha {
+		k := 2 * float64(j+1)
+		xi += a * math.Sin(k*xip) * math.Cosh(k*etap)
+		eta += a * math.Cos(k*xip) * math.Sinh(k*etap)
+	}
+	return tm.ka * eta, tm.ka * xi
+}
+
+// inverse 投影坐标 -> 经纬度
+func (tm *transverseMercator) inverse(x, y float64) (float64, float64) {
+	eta := (x - tm.fe) / tm.ka
+	xi := (y - tm.fn + tm.y0) / tm.ka
+
+	xip, etap := xi, eta
+	for j, b := range tm.beta {
+		k := 2 * float64(j+1)
+		xip -= b * math.Sin(k*xi) * math.Cosh(k*eta)
+		etap -= b * math.Cos(k*xi) * math.Sinh(k*eta)
+	}
+
+	sinhEta, cosXi := math.Sinh(etap), math.Cos(xip)
+	taup := math.Sin(xip) / math.Hypot(sinhEta, cosXi)
+	lam := math.Atan2(sinhEta, cosXi)
+
+	// 牛顿迭代由共形纬度求大地纬度
+	e2 := tm.e * tm.e
+	tau := taup / (1 - e2)
+	for i := 0; i < 5; i++ {
+		sigma := math.Sinh(tm.e * math.Atanh(tm.e*tau/math.Hypot(1, tau)))
+		tp := tau*math.Hypot(1, sigma) - sigma*math.Hypot(1, tau)
+		d := (taup - tp) / math.Hypot(1, tp) * (1 + (1-e2)*tau*tau) / ((1 - e2) * math.Hypot(1, tau))
+		tau += d
+		if math.Abs(d) < 1e-14 {
+			break
+		}
+	}
+	return (tm.lon0 + lam) * RadToDeg, math.Atan(tau) * RadToDeg
+}
+
+// steps 返回地理坐标系与投影坐标系之间的正反算转换函数
+func (tm *transverseMercator) steps() (forward, inverse pointStep) {
+	return stepOf(tm.forward), stepOf(tm.inverse)
+}
@@ -3,6 +3,7 @@ package gcoord
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
 )
 
 // 转换器注册和组合逻辑已移至 registry.go
@@ -10,10 +11,20 @@ import (
 // Transform 将输入从 crsFrom 转换到 crsTo。
 //
 // 支持的输入类型：
-//   - Position: []float64{lon, lat} 或 []float64{lon, lat, ...}
-//   - string: JSON 字符串（Position 或 GeoJSON 对象）
+//   - Position、[]float64: {lon, lat} 或 {lon, lat, z, m}，Z、M 等额外维度保持不变
+//   - [2]float64、[][2]float64、[]Position、[][]float64 以及多层嵌套的环（如 [][]Position、
+//     [][][]float64），返回新的切片
+//   - string: JSON 字符串（坐标数组或 GeoJSON 对象）、WKT/EWKT 或十六进制 WKB/EWKB，
+//     输出格式与输入相同
+//   - []byte: WKB/EWKB
+//   - Polyline: 编码折线，精度见 TransformOptions.PolylinePrecision
+//   - *SimpleGeometry: 原地转换，Z、M 保持不变
 //   - map[string]any: 任意 GeoJSON 对象（Point/LineString/Polygon/Feature/FeatureCollection/...）
-//   - []any: 坐标数组
+//   - FeatureCollection、Feature、Point 等类型化 GeoJSON 对象及其指针，以及 Geometry 接口，
+//     返回相同类型的结果。与 map 输入一样原地转换坐标
+//   - []any: 坐标数组（可多层嵌套）或 GeoJSON 对象数组
+//
+// 其他类型返回 ErrUnsupportedFormat 错误
 //
 // 转换精度：
 //   - 经纬度转换：约 1e-5 度（约 1 米）
@@ -25,50 +36,187 @@ import (
 //	feature, _ := Transform(geoJSON, WGS84, BD09)
 //	result, _ := Transform(`{"type":"Point","coordinates":[116.397,39.908]}`, WGS84, EPSG3857)
 func Transform[T any](input T, crsFrom, crsTo CRSTypes) (T, error) {
+	return TransformWithOptions(input, crsFrom, crsTo, TransformOptions{})
+}
+
+// TransformWithOptions 与 Transform 相同，但可通过 opts 控制转换行为，
+// 例如并行转换大型 FeatureCollection：
+//
+//	fc, err := TransformWithOptions(fc, WGS84, GCJ02, TransformOptions{Workers: 8})
+func TransformWithOptions[T any](input T, crsFrom, crsTo CRSTypes, opts TransformOptions) (T, error) {
 	var zero T
 
-	// 验证输入参数
-	if err := validateCRS(crsFrom); err != nil {
+	// 验证输入参数，并将别名解析为规范名称
+	crsFrom, err := validateCRS(crsFrom)
+	if err != nil {
 		return zero, err
 	}
-	if err := validateCRS(crsTo); err != nil {
+	crsTo, err = validateCRS(crsTo)
+	if err != nil {
 		return zero, err
 	}
 
-	if crsFrom == crsTo {
-		return input, nil
-	}
-
-	conv := getConverter(crsFrom, crsTo)
-	if conv == nil {
-		return zero, fmt.Errorf("无效的目标坐标系: %s", crsTo)
+	// 源与目标相同时为恒等转换，仍按类型分派，以便校验输入并应用 Strict、DropExtraDims、AddBBox 等选项
+	step := resolveStep(crsFrom, crsTo, &opts)
+	if step == nil {
+		return zero, ErrNoConverter(crsFrom, crsTo)
 	}
+	gt := &geoJSONTransformer{step: step, opts: opts, to: crsTo}
 
 	// 尝试类型分支
 	switch v := any(input).(type) {
 	case string:
+		if text := strings.TrimSpace(v); !json.Valid([]byte(text)) {
+			switch {
+			case looksLikeWKBHex(text):
+				g, err := ParseWKBHex(text)
+				if err != nil {
+					return zero, err
+				}
+				if err := transformGeometry(g, gt, crsTo); err != nil {
+					return zero, err
+				}
+				out := g.WKBHex()
+				if text == strings.ToLower(text) {
+					out = strings.ToLower(out)
+				}
+				return any(out).(T), nil
+			case looksLikeWKT(text):
+				g, err := ParseWKT(text)
+				if err != nil {
+					return zero, err
+				}
+				if err := transformGeometry(g, gt, crsTo); err != nil {
+					return zero, err
+				}
+				return any(g.WKT()).(T), nil
+			}
+		}
 		var obj any
 		if err := json.Unmarshal([]byte(v), &obj); err != nil {
 			return zero, ErrJSONParseFailed(err)
 		}
-		out := transformAny(obj, conv)
+		out, err := gt.document(obj)
+		if err != nil {
+			return zero, err
+		}
 		b, _ := json.Marshal(out)
 		return any(string(b)).(T), nil
-	case Position:
-		if err := validatePosition(v); err != nil {
+	case Polyline:
+		out, err := transformPolyline(v, gt)
+		if err != nil {
 			return zero, err
 		}
-		v = ensureNumberSlice(v)
-		return any(conv(v)).(T), nil
-	case []float64:
-		if err := validatePosition(Position(v)); err != nil {
+		return any(out).(T), nil
+	case []byte:
+		g, err := ParseWKB(v)
+		if err != nil {
+			return zero, err
+		}
+		if err := transformGeometry(g, gt, crsTo); err != nil {
+			return zero, err
+		}
+		return any(g.WKB()).(T), nil
+	case *SimpleGeometry:
+		if err := transformGeometry(v, gt, crsTo); err != nil {
+			return zero, err
+		}
+		return input, nil
+	case map[string]any, []any:
+		out, err := gt.document(v)
+		if err != nil {
 			return zero, err
 		}
-		return any(conv(Position(v))).(T), nil
-	default:
-		out := transformAny(v, conv)
 		return any(out).(T), nil
+	default:
+		if out, ok, err := transformSlices(v, gt); ok {
+			if err != nil {
+				return zero, err
+			}
+			return out.(T), nil
+		}
+		if ok, err := transformTyped(&input, gt); ok {
+			if err != nil {
+				return zero, err
+			}
+			return input, nil
+		}
+		return zero, &TransformError{
+			Type:    ErrUnsupportedFormat,
+			Message: fmt.Sprintf("不支持的输入类型: %T", input),
+			Details: map[string]interface{}{
+				"type": fmt.Sprintf("%T", input),
+			},
+		}
+	}
+}
+
+// transformSlices 转换坐标切片与数组，返回新的切片，输入不变。
+// 不是支持的类型时 ok 为 false
+func transformSlices(v any, gt *geoJSONTransformer) (out any, ok bool, err error) {
+	vec := func(p []float64) ([]float64, error) { return vecPoint(gt, p) }
+	pos := func(p Position) (Position, error) { return vecPoint(gt, p) }
+	ring := func(r []Position) ([]Position, error) { return mapSlice(r, pos) }
+	vecRing := func(r [][]float64) ([][]float64, error) { return mapSlice(r, vec) }
+
+	switch c := v.(type) {
+	case Position:
+		out, err = pos(c)
+	case []float64:
+		out, err = vec(c)
+	case [2]float64:
+		out, err = gt.pair(c)
+	case [][2]float64:
+		out, err = mapSlice(c, gt.pair)
+	case []Position:
+		out, err = ring(c)
+	case [][]Position:
+		out, err = mapSlice(c, ring)
+	case [][][]Position:
+		out, err = mapSlice(c, func(p [][]Position) ([][]Position, error) { return mapSlice(p, ring) })
+	case [][]float64:
+		out, err = vecRing(c)
+	case [][][]float64:
+		out, err = mapSlice(c, vecRing)
+	case [][][][]float64:
+		out, err = mapSlice(c, func(p [][][]float64) ([][][]float64, error) { return mapSlice(p, vecRing) })
+	default:
+		return nil, false, nil
+	}
+	return out, true, atRoot(err)
+}
+
+// vecPoint 转换一个坐标，返回新的切片，Z、M 等额外维度默认保留
+func vecPoint[S ~[]float64](gt *geoJSONTransformer, p S) (S, error) {
+	if len(p) < 2 {
+		return nil, ErrInvalidPosition
+	}
+	x, y, err := gt.point(p[0], p[1])
+	if err != nil {
+		return nil, err
+	}
+	if gt.opts.DropExtraDims {
+		return S{x, y}, nil
+	}
+	return S(withXY(Position(p), x, y)), nil
+}
+
+// pair 转换 [2]float64 形式的坐标
+func (gt *geoJSONTransformer) pair(p [2]float64) ([2]float64, error) {
+	x, y, err := gt.point(p[0], p[1])
+	return [2]float64{x, y}, err
+}
+
+// mapSlice 对每个元素调用 fn 并返回新的切片，出错时在错误路径前加上元素序号
+func mapSlice[E any](items []E, fn func(E) (E, error)) ([]E, error) {
+	out := make([]E, len(items))
+	for i, item := range items {
+		var err error
+		if out[i], err = fn(item); err != nil {
+			return nil, withPath(err, fmt.Sprintf("[%d]", i))
+		}
 	}
+	return out, nil
 }
 
 // 工具函数已移至 utils.go
@@ -41,6 +41,54 @@ func TestWGS84GCJ02Roundtrip(t *testing.T) {
 	}
 }
 
+func TestDeltaJacobian(t *testing.T) {
+	const h = 1e-7
+	for _, p := range []Position{{116.397, 39.908}, {121.473, 31.230}, {87.617, 43.793}, {105.5, 20.1}} {
+		a, b, c, d := deltaJacobian(p[0], p[1])
+		lon1, lat1 := delta(p[0]+h, p[1])
+		lon0, lat0 := delta(p[0]-h, p[1])
+		lon3, lat3 := delta(p[0], p[1]+h)
+		lon2, lat2 := delta(p[0], p[1]-h)
+		want := []float64{(lon1 - lon0) / (2 * h), (lon3 - lon2) / (2 * h), (lat1 - lat0) / (2 * h), (lat3 - lat2) / (2 * h)}
+		for i, got := range []float64{a, b, c, d} {
+			if !approx(got, want[i], 1e-6) {
+				t.Fatalf("jacobian[%d] at %v: got %v want %v", i, p, got, want[i])
+			}
+		}
+	}
+}
+
+func TestGCJ02InversePrecision(t *testing.T) {
+	// 经度 105 附近 delta 的导数趋于无穷，不动点迭代收敛变慢
+	for _, src := range []Position{{116.397, 39.908}, {105, 30}, {105.00000001, 30}} {
+		gcj := WGS84ToGCJ02(src)
+		for _, precision := range []float64{1e-6, 1e-9, 1e-12} {
+			opts := TransformOptions{InversePrecision: precision}
+			back, err := TransformWithOptions(gcj, GCJ02, WGS84, opts)
+			if err != nil {
+				t.Fatalf("%v precision %g: %v", src, precision, err)
+			}
+			if !approxPos(WGS84ToGCJ02(back), gcj, precision) {
+				t.Fatalf("%v precision %g: residual too large, got %v", src, precision, back)
+			}
+		}
+	}
+}
+
+func TestGCJ02InverseNotConverged(t *testing.T) {
+	gcj := WGS84ToGCJ02(Position{116.397, 39.908})
+	_, err := TransformWithOptions(gcj, GCJ02, WGS84, TransformOptions{InversePrecision: 1e-12, MaxIterations: 1})
+	if GetErrorType(err) != ErrTransformFailed {
+		t.Fatalf("expect ErrTransformFailed, got %v", err)
+	}
+
+	// NaN 不进入迭代，原样返回
+	out, err := Transform(Position{math.NaN(), 39.9}, GCJ02, WGS84)
+	if err != nil || !math.IsNaN(out[0]) {
+		t.Fatalf("NaN input: got %v, %v", out, err)
+	}
+}
+
 func TestGCJ02BD09Roundtrip(t *testing.T) {
 	src := Position{116.404, 39.915}
 	to, err := Transform(src, GCJ02, BD09)
@@ -56,6 +104,51 @@ func TestGCJ02BD09Roundtrip(t *testing.T) {
 	}
 }
 
+// maxBD09Drift 返回全国网格上 GCJ02→BD09→GCJ02 往返的最大误差（度）
+func maxBD09Drift(t *testing.T, opts TransformOptions) float64 {
+	t.Helper()
+	worst := 0.0
+	for lon := 73.0; lon < 135; lon += 1.7 {
+		for lat := 18.0; lat < 54; lat += 1.3 {
+			src := Position{lon, lat}
+			bd, err := TransformWithOptions(src, GCJ02, BD09, opts)
+			if err != nil {
+				t.Fatalf("forward %v: %v", src, err)
+			}
+			back, err := TransformWithOptions(bd, BD09, GCJ02, opts)
+			if err != nil {
+				t.Fatalf("inverse %v: %v", bd, err)
+			}
+			worst = math.Max(worst, math.Max(math.Abs(back[0]-lon), math.Abs(back[1]-lat)))
+		}
+	}
+	return worst
+}
+
+func TestBD09ExactRoundtrip(t *testing.T) {
+	// 近似公式：往返误差为厘米级（1e-6 度约 11 厘米）
+	approxDrift := maxBD09Drift(t, TransformOptions{})
+	if approxDrift < 1e-7 || approxDrift > 5e-6 {
+		t.Fatalf("approximate drift out of expected range: %g", approxDrift)
+	}
+	// 精确模式：小于 1e-9 度（约 0.1 毫米）
+	exactDrift := maxBD09Drift(t, TransformOptions{ExactBD09: true})
+	if exactDrift > 1e-9 {
+		t.Fatalf("exact drift too large: %g", exactDrift)
+	}
+	t.Logf("BD09 round trip drift: approximate %.3g deg, exact %.3g deg", approxDrift, exactDrift)
+
+	conv, err := NewConverterWithOptions(BD09, GCJ02, TransformOptions{ExactBD09: true})
+	if err != nil {
+		t.Fatalf("NewConverterWithOptions: %v", err)
+	}
+	src := Position{116.404, 39.915}
+	got, err := conv.Convert(GCJ02ToBD09(src))
+	if err != nil || !approxPos(got, src, 1e-9) {
+		t.Fatalf("exact converter: got %v, %v", got, err)
+	}
+}
+
 func TestBD09LLBD09MCRoundtrip(t *testing.T) {
 	src := Position{116.404, 39.915}
 	to, err := Transform(src, BD09, BD09MC)
@@ -113,7 +206,11 @@ func TestPreserveExtraDimensions(t *testing.T) {
 		"type":        "Point",
 		"coordinates": src,
 	}
-	outAny := transformAny(m, func(p Position) Position { return WGS84ToGCJ02(p) })
+	gt := &geoJSONTransformer{step: wgs84ToGCJ02Step}
+	outAny, err := gt.transform(m)
+	if err != nil {
+		t.Fatalf("transform: %v", err)
+	}
 	out := outAny.(map[string]any)
 	coords := out["coordinates"].([]any)
 
@@ -148,8 +245,12 @@ func TestGeometryCollectionTransform(t *testing.T) {
 		},
 	}
 
-	out := transformAny(gc, func(p Position) Position { return WGS84ToGCJ02(p) }).(map[string]any)
-	geoms := out["geometries"].([]any)
+	gt := &geoJSONTransformer{step: wgs84ToGCJ02Step}
+	outAny, err := gt.transform(gc)
+	if err != nil {
+		t.Fatalf("transform: %v", err)
+	}
+	geoms := outAny.(map[string]any)["geometries"].([]any)
 
 	// 检查 Point 已变化
 	p := geoms[0].(map[string]any)["coordinates"].([]any)
@@ -165,3 +266,264 @@ func TestGeometryCollectionTransform(t *testing.T) {
 		}
 	}
 }
+
+func TestTransformFlat(t *testing.T) {
+	// 三维交错数组：仅转换前两维
+	coords := []float64{116.397, 39.908, 50, 121.473, 31.230, 8}
+	if err := TransformFlat(coords, 3, WGS84, BD09); err != nil {
+		t.Fatalf("flat transform error: %v", err)
+	}
+	for i, src := range []Position{{116.397, 39.908}, {121.473, 31.230}} {
+		want, _ := Transform(src, WGS84, BD09)
+		got := Position(coords[i*3 : i*3+2])
+		if !approxPos(got, want, 1e-12) {
+			t.Fatalf("flat mismatch at %d: got %v want %v", i, got, want)
+		}
+	}
+	if coords[2] != 50 || coords[5] != 8 {
+		t.Fatalf("extra dimension changed: %v", coords)
+	}
+
+	if err := TransformFlat([]float64{1, 2, 3}, 2, WGS84, GCJ02); GetErrorType(err) != ErrInvalidInput {
+		t.Fatalf("expect ErrInvalidInput for odd length, got %v", err)
+	}
+	if err := TransformFlat([]float64{1, 2}, 1, WGS84, GCJ02); GetErrorType(err) != ErrInvalidInput {
+		t.Fatalf("expect ErrInvalidInput for dim 1, got %v", err)
+	}
+}
+
+func TestTransformPoints(t *testing.T) {
+	points := [][2]float64{{12960000, 4830000}, {13520000, 3620000}}
+	src := append([][2]float64(nil), points...)
+	if err := TransformPoints(points, BD09MC, WGS84); err != nil {
+		t.Fatalf("points transform error: %v", err)
+	}
+	for i := range points {
+		want, _ := Transform(Position{src[i][0], src[i][1]}, BD09MC, WGS84)
+		if !approxPos(Position(points[i][:]), want, 1e-12) {
+			t.Fatalf("points mismatch at %d: got %v want %v", i, points[i], want)
+		}
+	}
+}
+
+func TestBatchZeroAlloc(t *testing.T) {
+	coords := []float64{116.397, 39.908, 121.473, 31.230, 113.264, 23.129}
+	points := [][2]float64{{116.397, 39.908}, {121.473, 31.230}}
+	// 预热缓存
+	_ = TransformFlat(coords, 2, WGS84, BD09MC)
+	_ = TransformPoints(points, WGS84, BD09MC)
+
+	allocs := testing.AllocsPerRun(100, func() {
+		_ = TransformFlat(coords, 2, WGS84, BD09MC)
+		_ = TransformPoints(points, WGS84, BD09MC)
+	})
+	// 仅允许构造缓存键的少量分配，与点数无关
+	if allocs > 2 {
+		t.Fatalf("expect no per-point allocation, got %v allocs", allocs)
+	}
+}
+
+func makeFeatureCollection(n int) map[string]any {
+	features := make([]any, n)
+	for i := range features {
+		features[i] = map[string]any{
+			"type": "Feature",
+			"geometry": map[string]any{
+				"type":        "Point",
+				"coordinates": []any{116.0 + float64(i)*1e-4, 39.9},
+			},
+			"properties": map[string]any{"id": float64(i)},
+		}
+	}
+	return map[string]any{"type": "FeatureCollection", "features": features}
+}
+
+func TestTransformParallelFeatureCollection(t *testing.T) {
+	const n = 1000
+	seq, err := Transform(makeFeatureCollection(n), WGS84, BD09)
+	if err != nil {
+		t.Fatalf("sequential transform error: %v", err)
+	}
+	par, err := TransformWithOptions(makeFeatureCollection(n), WGS84, BD09, TransformOptions{Workers: 8})
+	if err != nil {
+		t.Fatalf("parallel transform error: %v", err)
+	}
+
+	seqFeatures := seq["features"].([]any)
+	parFeatures := par["features"].([]any)
+	for i := 0; i < n; i++ {
+		sf := seqFeatures[i].(map[string]any)
+		pf := parFeatures[i].(map[string]any)
+		if pf["properties"].(map[string]any)["id"] != float64(i) {
+			t.Fatalf("feature order not preserved at %d", i)
+		}
+		sc := sf["geometry"].(map[string]any)["coordinates"].([]any)
+		pc := pf["geometry"].(map[string]any)["coordinates"].([]any)
+		if sc[0] != pc[0] || sc[1] != pc[1] {
+			t.Fatalf("parallel result mismatch at %d: got %v want %v", i, pc, sc)
+		}
+	}
+}
+
+func TestTransformParallelFirstError(t *testing.T) {
+	fc := makeFeatureCollection(200)
+	// 第 50 与 150 个 feature 转换时 panic，应返回序号最小的错误
+	bad := map[float64]bool{116.0 + 50*1e-4: true, 116.0 + 150*1e-4: true}
+	gt := &geoJSONTransformer{
+		step: func(x, y float64, _ *TransformOptions) (float64, float64, error) {
+			if bad[x] {
+				panic("bad coordinate")
+			}
+			return x, y, nil
+		},
+		opts: TransformOptions{Workers: 4},
+	}
+	_, err := gt.transform(fc)
+	te, ok := err.(*TransformError)
+	if !ok || te.Type != ErrTransformFailed {
+		t.Fatalf("expect ErrTransformFailed, got %v", err)
+	}
+	if te.Details["index"] != 50 {
+		t.Fatalf("expect first error at index 50, got %v", te.Details["index"])
+	}
+}
+
+func TestPreserveZMAllPairs(t *testing.T) {
+	// 各坐标系中国境内的同一位置附近的点
+	sample := map[CRSTypes]Position{
+		WGS84:    {116.397, 39.908},
+		GCJ02:    {116.403, 39.909},
+		BD09:     {116.410, 39.915},
+		BD09MC:   {12957636, 4825923},
+		EPSG3857: {12957229, 4852457},
+	}
+	for from, p := range sample {
+		for to := range sample {
+			for _, extra := range [][]float64{{50}, {50, 7}} {
+				in := append(append(Position{}, p...), extra...)
+
+				got, err := Transform(in, from, to)
+				if err != nil || len(got) != len(in) || got[2] != 50 || (len(in) == 4 && got[3] != 7) {
+					t.Fatalf("Transform %s→%s: %v (%v)", from, to, got, err)
+				}
+				flat, err := Transform([]float64(in), from, to)
+				if err != nil || len(flat) != len(in) || flat[2] != 50 {
+					t.Fatalf("Transform []float64 %s→%s: %v (%v)", from, to, flat, err)
+				}
+				conv, _ := NewConverter(from, to)
+				if got, err := conv.Convert(in); err != nil || len(got) != len(in) || got[2] != 50 {
+					t.Fatalf("Converter %s→%s: %v (%v)", from, to, got, err)
+				}
+
+				geo, err := Transform(map[string]any{"type": "Point", "coordinates": []any{in[0], in[1], 50.0}}, from, to)
+				if c := geo["coordinates"].([]any); err != nil || len(c) != 3 || c[2] != 50.0 {
+					t.Fatalf("GeoJSON %s→%s: %v (%v)", from, to, c, err)
+				}
+			}
+		}
+	}
+
+	if got, _ := TransformWithOptions(Position{116.397, 39.908, 50}, WGS84, GCJ02, TransformOptions{DropExtraDims: true}); len(got) != 2 {
+		t.Fatalf("DropExtraDims: %v", got)
+	}
+
+	// 直接转换函数
+	in := Position{116.397, 39.908, 50, 7}
+	for name, fn := range map[string]func(Position) Position{
+		"WGS84ToGCJ02": WGS84ToGCJ02, "GCJ02ToWGS84": GCJ02ToWGS84,
+		"GCJ02ToBD09": GCJ02ToBD09, "BD09ToGCJ02": BD09ToGCJ02,
+		"BD09toBD09MC": BD09toBD09MC, "WGS84ToEPSG3857": WGS84ToEPSG3857,
+	} {
+		if got := fn(in); len(got) != 4 || got[2] != 50 || got[3] != 7 {
+			t.Fatalf("%s: %v", name, got)
+		}
+	}
+	for name, fn := range map[string]func(Position) Position{"BD09MCtoBD09": BD09MCtoBD09, "EPSG3857ToWGS84": EPSG3857ToWGS84} {
+		if got := fn(Position{12957636, 4825923, 50, 7}); len(got) != 4 || got[2] != 50 || got[3] != 7 {
+			t.Fatalf("%s: %v", name, got)
+		}
+	}
+}
+
+func TestTransformSliceInputs(t *testing.T) {
+	a, b := Position{116.397, 39.908}, Position{121.473, 31.230}
+	wa, wb := WGS84ToGCJ02(a), WGS84ToGCJ02(b)
+	check := func(name string, got []float64, want Position) {
+		t.Helper()
+		if !approxPos(got, want, 1e-12) {
+			t.Fatalf("%s: got %v want %v", name, got, want)
+		}
+	}
+
+	pair, err := Transform([2]float64{a[0], a[1]}, WGS84, GCJ02)
+	if err != nil {
+		t.Fatal(err)
+	}
+	check("[2]float64", pair[:], wa)
+
+	pairs, err := Transform([][2]float64{{a[0], a[1]}, {b[0], b[1]}}, WGS84, GCJ02)
+	if err != nil {
+		t.Fatal(err)
+	}
+	check("[][2]float64", pairs[1][:], wb)
+
+	in := [][]float64{{a[0], a[1], 10}, {b[0], b[1]}}
+	line, err := Transform(in, WGS84, GCJ02)
+	if err != nil || len(line[0]) != 3 || line[0][2] != 10 {
+		t.Fatalf("[][]float64: %v (%v)", line, err)
+	}
+	check("[][]float64", line[1], wb)
+	if in[0][0] != a[0] {
+		t.Fatal("input should not be modified")
+	}
+
+	ring, err := Transform([]Position{a, b}, WGS84, GCJ02)
+	if err != nil {
+		t.Fatal(err)
+	}
+	check("[]Position", ring[0], wa)
+
+	multi, err := Transform([][][]Position{{{a, b}}}, WGS84, GCJ02)
+	if err != nil {
+		t.Fatal(err)
+	}
+	check("[][][]Position", multi[0][0][1], wb)
+
+	rings, err := Transform([][][][]float64{{{a, b}}}, WGS84, GCJ02)
+	if err != nil {
+		t.Fatal(err)
+	}
+	check("[][][][]float64", rings[0][0][0], wa)
+
+	// 嵌套坐标数组的 []any 与 JSON 字符串
+	arr, err := Transform([]any{[]any{a[0], a[1]}, []any{b[0], b[1]}}, WGS84, GCJ02)
+	if err != nil {
+		t.Fatal(err)
+	}
+	check("[]any", []float64{arr[1].([]any)[0].(float64), arr[1].([]any)[1].(float64)}, wb)
+	s, err := Transform("[116.397,39.908]", WGS84, GCJ02)
+	if err != nil || s == "[116.397,39.908]" {
+		t.Fatalf("JSON position: %s (%v)", s, err)
+	}
+
+	_, err = TransformWithOptions([][]float64{{a[0], a[1]}, {a[0], 95}}, WGS84, GCJ02, TransformOptions{Strict: true})
+	if te, ok := err.(*TransformError); !ok || te.Details["path"] != "$[1]" {
+		t.Fatalf("unexpected error %#v", err)
+	}
+	if _, err := Transform([][]float64{{1}}, WGS84, GCJ02); err != ErrInvalidPosition {
+		t.Fatalf("expected ErrInvalidPosition, got %v", err)
+	}
+}
+
+func TestTransformUnsupportedInput(t *testing.T) {
+	type point struct{ X, Y float64 }
+	for _, in := range []any{point{116.397, 39.908}, 42, map[string]float64{"x": 1}, [3]float64{1, 2, 3}, nil} {
+		if _, err := Transform(in, WGS84, GCJ02); GetErrorType(err) != ErrUnsupportedFormat {
+			t.Fatalf("%T: expected ErrUnsupportedFormat, got %v", in, err)
+		}
+		// 源与目标相同（含别名）时同样拒绝
+		if _, err := Transform(in, WGS84, "EPSG:4326"); GetErrorType(err) != ErrUnsupportedFormat {
+			t.Fatalf("%T same CRS: expected ErrUnsupportedFormat, got %v", in, err)
+		}
+	}
+}
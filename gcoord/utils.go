@@ -1,13 +1,5 @@
 package gcoord
 
-// ensureNumberSlice 确保 Position 至少包含两个元素
-func ensureNumberSlice(p Position) Position {
-	if len(p) >= 2 {
-		return Position{p[0], p[1]}
-	}
-	return p
-}
-
 // validatePosition 验证 Position 是否有效
 func validatePosition(p Position) error {
 	if len(p) < 2 {
@@ -16,23 +8,32 @@ func validatePosition(p Position) error {
 	return nil
 }
 
-// validateCRS 验证坐标系是否有效
-func validateCRS(crs CRSTypes) error {
-	if crs == "" {
-		return ErrEmptyCRS
+// ValidatePosition 按坐标系的有效范围校验坐标：经纬度坐标系单位为度，投影坐标系单位为米。
+// 长度不足、NaN、Inf 或超出范围时返回 ErrInvalidInput 错误
+func ValidatePosition(p Position, crs CRSTypes) error {
+	if err := validatePosition(p); err != nil {
+		return err
 	}
-
-	validCRS := map[CRSTypes]bool{
-		WGS84:    true,
-		GCJ02:    true,
-		BD09:     true,
-		BD09MC:   true,
-		EPSG3857: true,
+	crs, err := validateCRS(crs)
+	if err != nil {
+		return err
 	}
+	info, _ := LookupCRS(crs)
+	return checkCoord(p[0], p[1], info)
+}
 
-	if !validCRS[crs] {
-		return ErrUnsupportedCRS(crs)
-	}
+// withXY 返回 p 的副本，前两维替换为 x、y，Z、M 等额外维度保持不变
+func withXY(p Position, x, y float64) Position {
+	out := make(Position, len(p))
+	out[0], out[1] = x, y
+	copy(out[2:], p[2:])
+	return out
+}
 
-	return nil
+// validateCRS 验证坐标系是否有效，返回解析别名后的规范名称
+func validateCRS(crs CRSTypes) (CRSTypes, error) {
+	if crs == "" {
+		return "", ErrEmptyCRS
+	}
+	return ParseCRS(string(crs))
 }
@@ -0,0 +1,110 @@
+package gcoord
+
+import (
+	"fmt"
+	"math"
+)
+
+// UTM 投影参数
+const (
+	utmK0            = 0.9996
+	utmFalseEasting  = 500000.0
+	utmFalseNorthing = 10000000.0 // 南半球
+)
+
+// UTM 返回 WGS84 UTM 投影坐标系，zone 为 1–60，north 为 false 时为南半球投影带。
+// 对应 EPSG:32601–32660（北半球）与 EPSG:32701–32760（南半球）。
+// 带号超出范围时返回的坐标系未注册，转换时返回 ErrUnsupportedCRS 错误。
+//
+// 示例：
+//
+//	crs := UTM(UTMZone(151.21, -33.87)) // UTM_ZONE_56S
+//	gcj, err := Transform(Position{334436, 6250816}, crs, GCJ02)
+func UTM(zone int, north bool) CRSTypes {
+	hemisphere := "N"
+	if !north {
+		hemisphere = "S"
+	}
+	return CRSTypes(fmt.Sprintf("UTM_ZONE_%d%s", zone, hemisphere))
+}
+
+// UTMZone 返回 WGS84 经纬度所在的 UTM 投影带与半球，
+// 包括挪威西南部（32V）与斯瓦尔巴群岛（31X–37X）的特殊分带。
+//
+// UTM 只定义在 80°S–84°N 之间，两极地区使用 UPS 投影，本库不支持。
+// 纬度超出该范围时不报错，仍按经度返回投影带，调用方需自行检查纬度
+func UTMZone(lon, lat float64) (zone int, north bool) {
+	if lon < -180 || lon > 180 {
+		lon = math.Mod(lon+180, 360)
+		if lon < 0 {
+			lon += 360
+		}
+		lon -= 180
+	}
+	zone = int(math.Floor((lon+180)/6)) + 1
+	if zone > 60 {
+		zone = 60
+	}
+
+	switch {
+	case lat >= 56 && lat < 64 && lon >= 3 && lon < 12:
+		zone = 32
+	case lat >= 72 && lat <= 84 && lon >= 0 && lon < 42:
+		switch {
+		case lon < 9:
+			zone = 31
+		case lon < 21:
+			zone = 33
+		case lon < 33:
+			zone = 35
+		default:
+			zone = 37
+		}
+	}
+	return zone, lat >= 0
+}
+
+// registerUTM 注册 WGS84 UTM 南北半球共 120 个投影带
+func registerUTM() {
+	for _, north := range []bool{true, false} {
+		for zone := 1; zone <= 60; zone++ {
+			registerUTMZone(zone, north)
+		}
+	}
+}
+
+// registerUTMZone 注册一个 UTM 投影带
+func registerUTMZone(zone int, north bool) {
+	code, hemisphere, desc := 32600+zone, "N", "北半球"
+	fn := 0.0
+	// 有效范围在赤道另一侧留出约 9° 的余量
+	extent := [4]float64{utmFalseEasting - 1e6, -1e6, utmFalseEasting + 1e6, tmMaxNorthing}
+	if !north {
+		code, hemisphere, desc = 32700+zone, "S", "南半球"
+		fn = utmFalseNorthing
+		extent[1], extent[3] = fn-tmMaxNorthing, fn+1e6
+	}
+
+	name := UTM(zone, north)
+	mustRegisterCRS(CRSInfo{
+		Name:        name,
+		Description: fmt.Sprintf("WGS84 UTM 投影第 %d 带（%s）", zone, desc),
+		Aliases: []string{
+			fmt.Sprintf("EPSG:%d", code),
+			fmt.Sprintf("WGS 84 / UTM zone %d%s", zone, hemisphere),
+			fmt.Sprintf("UTM%d%s", zone, hemisphere),
+		},
+		Projected: true,
+		Extent:    extent,
+	})
+
+	tm := newTransverseMercator(WGS84Ellipsoid, TransverseMercator{
+		CentralMeridian: float64(6*zone - 183),
+		ScaleFactor:     utmK0,
+		FalseEasting:    utmFalseEasting,
+		FalseNorthing:   fn,
+	})
+	forward, inverse := tm.steps()
+	registerSteps(WGS84, map[CRSTypes]pointStep{name: forward})
+	registerSteps(name, map[CRSTypes]pointStep{WGS84: inverse})
+}
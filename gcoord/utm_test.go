@@ -0,0 +1,77 @@
+package gcoord
+
+import "testing"
+
+func TestUTMZone(t *testing.T) {
+	tests := []struct {
+		name     string
+		lon, lat float64
+		zone     int
+		north    bool
+	}{
+		{"北京", 116.397, 39.908, 50, true},
+		{"悉尼", 151.21, -33.87, 56, false},
+		{"纽约", -74.006, 40.7128, 18, true},
+		{"卑尔根", 5.32, 60.39, 32, true},
+		{"朗伊尔城", 15.63, 78.22, 33, true},
+		{"日界线", 180, 0, 60, true},
+		{"经度越界", 183, 10, 1, true},
+		// 极区不属于 UTM，仍按经度分带
+		{"北极", 116.397, 88, 50, true},
+		{"南极", 166.67, -85, 58, false},
+		{"南极点", 0, -90, 31, false},
+	}
+	for _, tt := range tests {
+		zone, north := UTMZone(tt.lon, tt.lat)
+		if zone != tt.zone || north != tt.north {
+			t.Errorf("%s: UTMZone = %d, %v, want %d, %v", tt.name, zone, north, tt.zone, tt.north)
+		}
+	}
+}
+
+func TestUTMTransform(t *testing.T) {
+	// 纽约市政厅，UTM 18N
+	xy, err := Transform(Position{-74.0060, 40.7128}, WGS84, UTM(UTMZone(-74.0060, 40.7128)))
+	if err != nil {
+		t.Fatalf("transform: %v", err)
+	}
+	if !approx(xy[0], 583959.37, 0.01) || !approx(xy[1], 4507350.99, 0.01) {
+		t.Fatalf("UTM 18N = %.3f, %.3f", xy[0], xy[1])
+	}
+
+	// 南半球伪北偏移
+	if eq, _ := Transform(Position{153, 0}, WGS84, UTM(56, false)); !approx(eq[0], 500000, 1e-6) || !approx(eq[1], 10000000, 1e-6) {
+		t.Fatalf("equator in 56S = %v", eq)
+	}
+
+	for code, crs := range map[string]CRSTypes{
+		"EPSG:32650":            UTM(50, true),
+		"EPSG:32756":            UTM(56, false),
+		"WGS 84 / UTM zone 50N": UTM(50, true),
+		"utm56s":                UTM(56, false),
+	} {
+		if parsed, err := ParseCRS(code); err != nil || parsed != crs {
+			t.Errorf("ParseCRS(%q) = %v, %v, want %v", code, parsed, err, crs)
+		}
+	}
+
+	// UTM 直接转换到 GCJ02 并往返
+	src := Position{116.397, 39.908}
+	utm, _ := Transform(src, WGS84, UTM(50, true))
+	gcj, err := Transform(utm, UTM(50, true), GCJ02)
+	if err != nil {
+		t.Fatalf("UTM -> GCJ02: %v", err)
+	}
+	want, _ := Transform(src, WGS84, GCJ02)
+	if !approxPos(gcj, want, 1e-9) {
+		t.Fatalf("UTM -> GCJ02 = %v, want %v", gcj, want)
+	}
+	back, err := Transform(gcj, GCJ02, UTM(50, true))
+	if err != nil || !approx(back[0], utm[0], 0.1) || !approx(back[1], utm[1], 0.1) {
+		t.Fatalf("GCJ02 -> UTM = %v, %v, want %v", back, err, utm)
+	}
+
+	if _, err := Transform(src, WGS84, UTM(61, true)); err == nil {
+		t.Fatalf("expected error for zone 61")
+	}
+}
@@ -0,0 +1,314 @@
+package gcoord
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// EWKB（PostGIS 扩展 WKB）类型标志位
+const (
+	ewkbZ    = 0x80000000
+	ewkbM    = 0x40000000
+	ewkbSRID = 0x20000000
+)
+
+// ParseWKB 解析 WKB，支持 ISO WKB（Z/M 类型代码 +1000/+2000/+3000）
+// 与 PostGIS EWKB（Z/M/SRID 标志位），大端与小端字节序均可。
+// 输出 WKB 时保持输入的字节序与格式。
+func ParseWKB(b []byte) (*SimpleGeometry, error) {
+	r := &wkbReader{b: b}
+	g, err := r.geometry(true)
+	if err != nil {
+		return nil, err
+	}
+	if r.pos != len(b) {
+		return nil, errWKBParse(r.pos, "几何之后存在多余字节")
+	}
+	return g, nil
+}
+
+// ParseWKBHex 解析十六进制编码的 WKB/EWKB，如 PostGIS 的默认输出
+func ParseWKBHex(s string) (*SimpleGeometry, error) {
+	b, err := hex.DecodeString(strings.TrimSpace(s))
+	if err != nil {
+		return nil, &TransformError{
+			Type:    ErrInvalidInput,
+			Message: fmt.Sprintf("WKB十六进制解码失败: %v", err),
+			Details: map[string]interface{}{
+				"original_error": err,
+			},
+		}
+	}
+	return ParseWKB(b)
+}
+
+// WKB 将几何输出为 WKB。
+//
+// 默认输出小端 ISO WKB；几何由 EWKB 解析而来或 SRID 不为 0 时输出 EWKB。
+func (g *SimpleGeometry) WKB() []byte {
+	w := &wkbWriter{extended: g.extended || g.SRID != 0}
+	if g.bigEndian {
+		w.order = binary.BigEndian
+	} else {
+		w.order = binary.LittleEndian
+	}
+	w.geometry(g, true)
+	return w.b
+}
+
+// WKBHex 将几何输出为大写十六进制编码的 WKB
+func (g *SimpleGeometry) WKBHex() string {
+	return strings.ToUpper(hex.EncodeToString(g.WKB()))
+}
+
+// wkbReader WKB 解码器
+type wkbReader struct {
+	b   []byte
+	pos int
+}
+
+// geometry 解码一个几何，top 为 true 时记录字节序与格式
+func (r *wkbReader) geometry(top bool) (*SimpleGeometry, error) {
+	if r.pos >= len(r.b) {
+		return nil, errWKBParse(r.pos, "数据不完整")
+	}
+	var order binary.ByteOrder
+	switch r.b[r.pos] {
+	case 0:
+		order = binary.BigEndian
+	case 1:
+		order = binary.LittleEndian
+	default:
+		return nil, errWKBParse(r.pos, fmt.Sprintf("无效的字节序标记 %d", r.b[r.pos]))
+	}
+	r.pos++
+
+	code, err := r.uint32(order)
+	if err != nil {
+		return nil, err
+	}
+	g := &SimpleGeometry{
+		HasZ:      code&ewkbZ != 0,
+		HasM:      code&ewkbM != 0,
+		bigEndian: order == binary.BigEndian,
+		extended:  code&(ewkbZ|ewkbM|ewkbSRID) != 0,
+	}
+	base := code &^ (ewkbZ | ewkbM | ewkbSRID)
+	switch base / 1000 {
+	case 1:
+		g.HasZ = true
+	case 2:
+		g.HasM = true
+	case 3:
+		g.HasZ, g.HasM = true, true
+	}
+	g.Type = GeometryType(base % 1000)
+	if _, ok := geometryTypeNames[g.Type]; !ok || base/1000 > 3 {
+		return nil, &TransformError{
+			Type:    ErrUnsupportedFormat,
+			Message: fmt.Sprintf("不支持的 WKB 几何类型: %d", code),
+			Details: map[string]interface{}{
+				"type":   code,
+				"offset": r.pos - 4,
+			},
+		}
+	}
+	if code&ewkbSRID != 0 {
+		srid, err := r.uint32(order)
+		if err != nil {
+			return nil, err
+		}
+		if top {
+			g.SRID = int(srid)
+		}
+	}
+
+	dim := g.dim()
+	switch g.Type {
+	case GeomPoint:
+		pt, err := r.coords(order, 1, dim)
+		if err != nil {
+			return nil, err
+		}
+		// 空点以 NaN 坐标表示
+		if !math.IsNaN(pt[0][0]) || !math.IsNaN(pt[0][1]) {
+			g.Points = pt
+		}
+	case GeomLineString:
+		if g.Points, err = r.coordSeq(order, dim); err != nil {
+			return nil, err
+		}
+	case GeomPolygon, GeomTriangle:
+		n, err := r.count(order, 4)
+		if err != nil {
+			return nil, err
+		}
+		for i := 0; i < n; i++ {
+			ring, err := r.coordSeq(order, dim)
+			if err != nil {
+				return nil, err
+			}
+			g.Rings = append(g.Rings, ring)
+		}
+	default:
+		n, err := r.count(order, 5)
+		if err != nil {
+			return nil, err
+		}
+		for i := 0; i < n; i++ {
+			part, err := r.geometry(false)
+			if err != nil {
+				return nil, err
+			}
+			g.Geometries = append(g.Geometries, part)
+		}
+	}
+	return g, nil
+}
+
+// coordSeq 解码带点数前缀的坐标序列
+func (r *wkbReader) coordSeq(order binary.ByteOrder, dim int) ([][]float64, error) {
+	n, err := r.count(order, dim*8)
+	if err != nil {
+		return nil, err
+	}
+	return r.coords(order, n, dim)
+}
+
+// coords 解码 n 个 dim 维坐标
+func (r *wkbReader) coords(order binary.ByteOrder, n, dim int) ([][]float64, error) {
+	if len(r.b)-r.pos < n*dim*8 {
+		return nil, errWKBParse(r.pos, "数据不完整")
+	}
+	flat := make([]float64, n*dim)
+	for i := range flat {
+		flat[i] = math.Float64frombits(order.Uint64(r.b[r.pos:]))
+		r.pos += 8
+	}
+	pts := make([][]float64, n)
+	for i := range pts {
+		pts[i] = flat[i*dim : (i+1)*dim : (i+1)*dim]
+	}
+	return pts, nil
+}
+
+// count 读取元素个数，并按每个元素的最小字节数检查是否超出剩余数据
+func (r *wkbReader) count(order binary.ByteOrder, minSize int) (int, error) {
+	v, err := r.uint32(order)
+	if err != nil {
+		return 0, err
+	}
+	if uint64(v)*uint64(minSize) > uint64(len(r.b)-r.pos) {
+		return 0, errWKBParse(r.pos-4, fmt.Sprintf("元素个数 %d 超出数据长度", v))
+	}
+	return int(v), nil
+}
+
+func (r *wkbReader) uint32(order binary.ByteOrder) (uint32, error) {
+	if len(r.b)-r.pos < 4 {
+		return 0, errWKBParse(r.pos, "数据不完整")
+	}
+	v := order.Uint32(r.b[r.pos:])
+	r.pos += 4
+	return v, nil
+}
+
+// errWKBParse 创建 WKB 解析失败错误，offset 为出错的字节偏移
+func errWKBParse(offset int, msg string) *TransformError {
+	return &TransformError{
+		Type:    ErrInvalidInput,
+		Message: fmt.Sprintf("WKB解析失败: %s (偏移 %d)", msg, offset),
+		Details: map[string]interface{}{
+			"offset": offset,
+		},
+	}
+}
+
+// wkbWriter WKB 编码器
+type wkbWriter struct {
+	b        []byte
+	order    binary.AppendByteOrder
+	extended bool
+}
+
+func (w *wkbWriter) geometry(g *SimpleGeometry, top bool) {
+	if w.order == binary.BigEndian {
+		w.b = append(w.b, 0)
+	} else {
+		w.b = append(w.b, 1)
+	}
+
+	code := uint32(g.Type)
+	if w.extended {
+		if g.HasZ {
+			code |= ewkbZ
+		}
+		if g.HasM {
+			code |= ewkbM
+		}
+		if top && g.SRID != 0 {
+			code |= ewkbSRID
+		}
+	} else {
+		if g.HasZ {
+			code += 1000
+		}
+		if g.HasM {
+			code += 2000
+		}
+	}
+	w.uint32(code)
+	if code&ewkbSRID != 0 && w.extended {
+		w.uint32(uint32(g.SRID))
+	}
+
+	dim := g.dim()
+	switch g.Type {
+	case GeomPoint:
+		if len(g.Points) == 0 {
+			for i := 0; i < dim; i++ {
+				w.float64(math.NaN())
+			}
+			return
+		}
+		w.coords(g.Points[0:1], dim)
+	case GeomLineString:
+		w.uint32(uint32(len(g.Points)))
+		w.coords(g.Points, dim)
+	case GeomPolygon, GeomTriangle:
+		w.uint32(uint32(len(g.Rings)))
+		for _, ring := range g.Rings {
+			w.uint32(uint32(len(ring)))
+			w.coords(ring, dim)
+		}
+	default:
+		w.uint32(uint32(len(g.Geometries)))
+		for _, part := range g.Geometries {
+			w.geometry(part, false)
+		}
+	}
+}
+
+// coords 写出坐标，缺失的维度补 0
+func (w *wkbWriter) coords(pts [][]float64, dim int) {
+	for _, pt := range pts {
+		for i := 0; i < dim; i++ {
+			if i < len(pt) {
+				w.float64(pt[i])
+			} else {
+				w.float64(0)
+			}
+		}
+	}
+}
+
+func (w *wkbWriter) uint32(v uint32) {
+	w.b = w.order.AppendUint32(w.b, v)
+}
+
+func (w *wkbWriter) float64(v float64) {
+	w.b = w.order.AppendUint64(w.b, math.Float64bits(v))
+}
@@ -0,0 +1,472 @@
+package gcoord
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ParseWKT 解析 WKT 或 EWKT 字符串。
+//
+// 支持 OGC 简单要素的全部类型及 Z、M、ZM 维度，维度可写为独立标记
+// （"POINT Z (1 2 3)"）或类型后缀（"POINTZ(1 2 3)"），省略时按坐标个数推断
+// （3 个为 Z，4 个为 ZM）。支持 EWKT 的 "SRID=4326;" 前缀。
+//
+// 示例：
+//
+//	g, err := ParseWKT("LINESTRING (116.397 39.908, 116.404 39.915)")
+func ParseWKT(s string) (*SimpleGeometry, error) {
+	s = strings.TrimSpace(s)
+	srid := 0
+	if len(s) > 5 && strings.EqualFold(s[:5], "SRID=") {
+		i := strings.IndexByte(s, ';')
+		if i < 0 {
+			return nil, errWKTParse(s, 5, "SRID 后缺少 ';'")
+		}
+		n, err := strconv.Atoi(strings.TrimSpace(s[5:i]))
+		if err != nil {
+			return nil, errWKTParse(s, 5, "无效的 SRID")
+		}
+		srid = n
+		s = s[i+1:]
+	}
+
+	p := &wktParser{s: s}
+	g, err := p.geometry()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	if p.pos < len(p.s) {
+		return nil, p.errorf("几何之后存在多余内容")
+	}
+	g.SRID = srid
+	return g, nil
+}
+
+// WKT 将几何输出为 WKT，SRID 不为 0 时输出 EWKT
+func (g *SimpleGeometry) WKT() string {
+	var b strings.Builder
+	if g.SRID != 0 {
+		fmt.Fprintf(&b, "SRID=%d;", g.SRID)
+	}
+	writeWKT(&b, g)
+	return b.String()
+}
+
+// String 返回几何的 WKT 表示
+func (g *SimpleGeometry) String() string {
+	return g.WKT()
+}
+
+// wktParser WKT 递归下降解析器
+type wktParser struct {
+	s   string
+	pos int
+}
+
+// wktDims 几何及其成员共享的维度，known 为 false 时由第一个坐标推断
+type wktDims struct {
+	z, m, known bool
+}
+
+// geometry 解析带类型名的几何
+func (p *wktParser) geometry() (*SimpleGeometry, error) {
+	word := strings.ToUpper(p.word())
+	if word == "" {
+		return nil, p.errorf("期望几何类型")
+	}
+	typ, dims, ok := parseWKTType(word)
+	if !ok {
+		return nil, &TransformError{
+			Type:    ErrUnsupportedFormat,
+			Message: fmt.Sprintf("不支持的 WKT 几何类型: %s", word),
+			Details: map[string]interface{}{
+				"type": word,
+			},
+		}
+	}
+
+	// 独立的维度标记
+	if !dims.known {
+		save := p.pos
+		switch strings.ToUpper(p.word()) {
+		case "Z":
+			dims = wktDims{z: true, known: true}
+		case "M":
+			dims = wktDims{m: true, known: true}
+		case "ZM":
+			dims = wktDims{z: true, m: true, known: true}
+		default:
+			p.pos = save
+		}
+	}
+
+	g := &SimpleGeometry{Type: typ}
+	if err := p.body(g, &dims); err != nil {
+		return nil, err
+	}
+	g.HasZ, g.HasM = dims.z, dims.m
+	finishDims(g)
+	return g, nil
+}
+
+// body 按类型解析几何的坐标部分
+func (p *wktParser) body(g *SimpleGeometry, dims *wktDims) error {
+	if p.empty() {
+		return nil
+	}
+	var err error
+	switch g.Type {
+	case GeomPoint:
+		if err = p.expect('('); err != nil {
+			return err
+		}
+		var pt []float64
+		if pt, err = p.coord(dims); err != nil {
+			return err
+		}
+		g.Points = [][]float64{pt}
+		return p.expect(')')
+	case GeomLineString:
+		g.Points, err = p.coordSeq(dims)
+		return err
+	case GeomPolygon, GeomTriangle:
+		g.Rings, err = p.ringSeq(dims)
+		return err
+	case GeomGeometryCollection:
+		return p.list(func() error {
+			part, err := p.geometry()
+			if err != nil {
+				return err
+			}
+			if !dims.known && !part.IsEmpty() {
+				*dims = wktDims{z: part.HasZ, m: part.HasM, known: true}
+			}
+			g.Geometries = append(g.Geometries, part)
+			return nil
+		})
+	default:
+		partType := memberType(g.Type)
+		return p.list(func() error {
+			part := &SimpleGeometry{Type: partType}
+			if g.Type == GeomMultiPoint && p.peek() != '(' && !p.peekEmpty() {
+				// MULTIPOINT (1 2, 3 4) 形式
+				pt, err := p.coord(dims)
+				if err != nil {
+					return err
+				}
+				part.Points = [][]float64{pt}
+			} else if err := p.body(part, dims); err != nil {
+				return err
+			}
+			g.Geometries = append(g.Geometries, part)
+			return nil
+		})
+	}
+}
+
+// finishDims 将推断出的维度写回成员几何
+func finishDims(g *SimpleGeometry) {
+	for _, part := range g.Geometries {
+		if g.Type != GeomGeometryCollection {
+			part.HasZ, part.HasM = g.HasZ, g.HasM
+		}
+		finishDims(part)
+	}
+}
+
+// memberType 返回集合类型的成员类型
+func memberType(t GeometryType) GeometryType {
+	switch t {
+	case GeomMultiPoint:
+		return GeomPoint
+	case GeomMultiLineString:
+		return GeomLineString
+	case GeomTIN:
+		return GeomTriangle
+	default:
+		return GeomPolygon
+	}
+}
+
+// parseWKTType 解析类型名，允许带 Z、M、ZM 后缀
+func parseWKTType(word string) (GeometryType, wktDims, bool) {
+	for typ, name := range geometryTypeNames {
+		if word == name {
+			return typ, wktDims{}, true
+		}
+	}
+	for _, suffix := range []struct {
+		s    string
+		dims wktDims
+	}{
+		{"ZM", wktDims{z: true, m: true, known: true}},
+		{"Z", wktDims{z: true, known: true}},
+		{"M", wktDims{m: true, known: true}},
+	} {
+		base, ok := strings.CutSuffix(word, suffix.s)
+		if !ok {
+			continue
+		}
+		for typ, name := range geometryTypeNames {
+			if base == name {
+				return typ, suffix.dims, true
+			}
+		}
+	}
+	return 0, wktDims{}, false
+}
+
+// coordSeq 解析 "(x y, x y, ...)" 或 EMPTY
+func (p *wktParser) coordSeq(dims *wktDims) ([][]float64, error) {
+	if p.empty() {
+		return nil, nil
+	}
+	var pts [][]float64
+	err := p.list(func() error {
+		pt, err := p.coord(dims)
+		if err != nil {
+			return err
+		}
+		pts = append(pts, pt)
+		return nil
+	})
+	return pts, err
+}
+
+// ringSeq 解析 "((...), (...))" 或 EMPTY
+func (p *wktParser) ringSeq(dims *wktDims) ([][][]float64, error) {
+	if p.empty() {
+		return nil, nil
+	}
+	var rings [][][]float64
+	err := p.list(func() error {
+		ring, err := p.coordSeq(dims)
+		if err != nil {
+			return err
+		}
+		rings = append(rings, ring)
+		return nil
+	})
+	return rings, err
+}
+
+// list 解析 "(item, item, ...)"
+func (p *wktParser) list(item func() error) error {
+	if err := p.expect('('); err != nil {
+		return err
+	}
+	for {
+		if err := item(); err != nil {
+			return err
+		}
+		if !p.accept(',') {
+			break
+		}
+	}
+	return p.expect(')')
+}
+
+// coord 解析一个以空白分隔的坐标，并检查维度一致
+func (p *wktParser) coord(dims *wktDims) ([]float64, error) {
+	start := p.pos
+	pt := make([]float64, 0, 4)
+	for {
+		p.skipSpace()
+		begin := p.pos
+		for p.pos < len(p.s) && strings.IndexByte("0123456789+-.eE", p.s[p.pos]) >= 0 {
+			p.pos++
+		}
+		if begin == p.pos {
+			break
+		}
+		text := p.s[begin:p.pos]
+		v, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			p.pos = begin
+			return nil, p.errorf("无效的数字 %q", text)
+		}
+		pt = append(pt, v)
+	}
+
+	if len(pt) < 2 || len(pt) > 4 {
+		p.pos = start
+		return nil, p.errorf("坐标应包含 2 到 4 个数字，得到 %d 个", len(pt))
+	}
+	if !dims.known {
+		*dims = wktDims{z: len(pt) >= 3, m: len(pt) == 4, known: true}
+	}
+	want := 2
+	if dims.z {
+		want++
+	}
+	if dims.m {
+		want++
+	}
+	if len(pt) != want {
+		p.pos = start
+		return nil, p.errorf("坐标维度不一致: 期望 %d 个数字，得到 %d 个", want, len(pt))
+	}
+	return pt, nil
+}
+
+// word 读取一个由字母组成的单词
+func (p *wktParser) word() string {
+	p.skipSpace()
+	begin := p.pos
+	for p.pos < len(p.s) {
+		c := p.s[p.pos]
+		if !(c >= 'A' && c <= 'Z') && !(c >= 'a' && c <= 'z') {
+			break
+		}
+		p.pos++
+	}
+	return p.s[begin:p.pos]
+}
+
+// empty 读取可选的 EMPTY 关键字
+func (p *wktParser) empty() bool {
+	save := p.pos
+	if strings.EqualFold(p.word(), "EMPTY") {
+		return true
+	}
+	p.pos = save
+	return false
+}
+
+// peekEmpty 检查下一个单词是否为 EMPTY，不消费
+func (p *wktParser) peekEmpty() bool {
+	save := p.pos
+	defer func() { p.pos = save }()
+	return p.empty()
+}
+
+// peek 返回下一个非空白字符
+func (p *wktParser) peek() byte {
+	p.skipSpace()
+	if p.pos < len(p.s) {
+		return p.s[p.pos]
+	}
+	return 0
+}
+
+func (p *wktParser) accept(c byte) bool {
+	if p.peek() == c {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *wktParser) expect(c byte) error {
+	if !p.accept(c) {
+		return p.errorf("期望 '%c'", c)
+	}
+	return nil
+}
+
+func (p *wktParser) skipSpace() {
+	for p.pos < len(p.s) {
+		switch p.s[p.pos] {
+		case ' ', '\t', '\r', '\n':
+			p.pos++
+		default:
+			return
+		}
+	}
+}
+
+func (p *wktParser) errorf(format string, args ...any) error {
+	return errWKTParse(p.s, p.pos, fmt.Sprintf(format, args...))
+}
+
+// errWKTParse 创建 WKT 解析失败错误，offset 为出错位置
+func errWKTParse(s string, offset int, msg string) *TransformError {
+	return &TransformError{
+		Type:    ErrInvalidInput,
+		Message: fmt.Sprintf("WKT解析失败: %s (位置 %d)", msg, offset),
+		Details: map[string]interface{}{
+			"offset": offset,
+			"wkt":    s,
+		},
+	}
+}
+
+// writeWKT 输出几何的 WKT 文本，不含 SRID
+func writeWKT(b *strings.Builder, g *SimpleGeometry) {
+	b.WriteString(g.Type.String())
+	switch {
+	case g.HasZ && g.HasM:
+		b.WriteString(" ZM")
+	case g.HasZ:
+		b.WriteString(" Z")
+	case g.HasM:
+		b.WriteString(" M")
+	}
+	b.WriteByte(' ')
+	writeWKTBody(b, g)
+}
+
+// writeWKTBody 输出几何的坐标部分
+func writeWKTBody(b *strings.Builder, g *SimpleGeometry) {
+	if g.IsEmpty() {
+		b.WriteString("EMPTY")
+		return
+	}
+	switch g.Type {
+	case GeomPoint, GeomLineString:
+		writeWKTCoords(b, g.Points)
+	case GeomPolygon, GeomTriangle:
+		writeWKTRings(b, g.Rings)
+	case GeomGeometryCollection:
+		b.WriteByte('(')
+		for i, part := range g.Geometries {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			writeWKT(b, part)
+		}
+		b.WriteByte(')')
+	default:
+		b.WriteByte('(')
+		for i, part := range g.Geometries {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			writeWKTBody(b, part)
+		}
+		b.WriteByte(')')
+	}
+}
+
+func writeWKTRings(b *strings.Builder, rings [][][]float64) {
+	b.WriteByte('(')
+	for i, ring := range rings {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		if len(ring) == 0 {
+			b.WriteString("EMPTY")
+			continue
+		}
+		writeWKTCoords(b, ring)
+	}
+	b.WriteByte(')')
+}
+
+func writeWKTCoords(b *strings.Builder, pts [][]float64) {
+	b.WriteByte('(')
+	for i, pt := range pts {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		for j, v := range pt {
+			if j > 0 {
+				b.WriteByte(' ')
+			}
+			b.WriteString(strconv.FormatFloat(v, 'f', -1, 64))
+		}
+	}
+	b.WriteByte(')')
+}
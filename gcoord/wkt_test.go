@@ -0,0 +1,175 @@
+package gcoord
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestWKTRoundTrip(t *testing.T) {
+	cases := []string{
+		"POINT (116.397 39.908)",
+		"POINT Z (116.397 39.908 50)",
+		"POINT M (116.397 39.908 7)",
+		"POINT ZM (116.397 39.908 50 7)",
+		"POINT EMPTY",
+		"LINESTRING (1 2, 3 4, 5 6)",
+		"POLYGON ((0 0, 10 0, 10 10, 0 0), (1 1, 2 1, 2 2, 1 1))",
+		"MULTIPOINT ((1 2), EMPTY, (3 4))",
+		"MULTILINESTRING Z ((1 2 3, 4 5 6), (7 8 9, 10 11 12))",
+		"MULTIPOLYGON (((0 0, 1 0, 1 1, 0 0)), ((5 5, 6 5, 6 6, 5 5)))",
+		"GEOMETRYCOLLECTION (POINT (1 2), LINESTRING M (1 2 3, 4 5 6))",
+		"GEOMETRYCOLLECTION EMPTY",
+		"POLYHEDRALSURFACE Z (((0 0 0, 0 1 0, 1 1 0, 0 0 0)), ((0 0 0, 0 1 0, 0 1 1, 0 0 0)))",
+		"TIN (((0 0, 0 1, 1 0, 0 0)))",
+		"TRIANGLE ((0 0, 0 1, 1 0, 0 0))",
+		"SRID=4326;POINT (1 2)",
+	}
+	for _, wkt := range cases {
+		g, err := ParseWKT(wkt)
+		if err != nil {
+			t.Fatalf("parse %q: %v", wkt, err)
+		}
+		if got := g.WKT(); got != wkt {
+			t.Fatalf("WKT round trip: got %q want %q", got, wkt)
+		}
+		back, err := ParseWKB(g.WKB())
+		if err != nil {
+			t.Fatalf("parse WKB of %q: %v", wkt, err)
+		}
+		if got := back.WKT(); got != wkt {
+			t.Fatalf("WKB round trip: got %q want %q", got, wkt)
+		}
+	}
+}
+
+func TestParseWKTVariants(t *testing.T) {
+	cases := map[string]string{
+		"point(1 2)":                  "POINT (1 2)",
+		"POINTZ(1 2 3)":               "POINT Z (1 2 3)",
+		"POINTM(1 2 3)":               "POINT M (1 2 3)",
+		"POINT(1 2 3)":                "POINT Z (1 2 3)",
+		"POINT(1 2 3 4)":              "POINT ZM (1 2 3 4)",
+		"MULTIPOINT (1 2, 3 4)":       "MULTIPOINT ((1 2), (3 4))",
+		"  LINESTRING(1 2,3 4)  ":     "LINESTRING (1 2, 3 4)",
+		"srid=3857;POINT(1e3 -2.5E2)": "SRID=3857;POINT (1000 -250)",
+	}
+	for in, want := range cases {
+		g, err := ParseWKT(in)
+		if err != nil {
+			t.Fatalf("parse %q: %v", in, err)
+		}
+		if got := g.WKT(); got != want {
+			t.Fatalf("parse %q: got %q want %q", in, got, want)
+		}
+	}
+
+	for _, bad := range []string{"POINT (1)", "LINESTRING (1 2, 3 4 5)", "POINT (1 2", "CIRCLE (1 2)", "POINT (1 2) x"} {
+		if _, err := ParseWKT(bad); err == nil {
+			t.Fatalf("expected error for %q", bad)
+		}
+	}
+	if _, err := ParseWKT("CIRCULARSTRING (1 2, 3 4)"); GetErrorType(err) != ErrUnsupportedFormat {
+		t.Fatalf("expected ErrUnsupportedFormat, got %v", err)
+	}
+}
+
+func TestParseWKBFormats(t *testing.T) {
+	// PostGIS: ST_AsEWKB('SRID=4326;POINT Z (1 2 3)')
+	ewkb := "01010000A0E6100000000000000000F03F00000000000000400000000000000840"
+	g, err := ParseWKBHex(ewkb)
+	if err != nil {
+		t.Fatalf("parse EWKB: %v", err)
+	}
+	if got := g.WKT(); got != "SRID=4326;POINT Z (1 2 3)" {
+		t.Fatalf("EWKB: got %q", got)
+	}
+	if got := g.WKBHex(); got != ewkb {
+		t.Fatalf("EWKB round trip: got %s", got)
+	}
+
+	// 大端 ISO WKB: POINT Z (1 2 3)
+	iso := "00000003E93FF000000000000040000000000000004008000000000000"
+	g, err = ParseWKBHex(iso)
+	if err != nil {
+		t.Fatalf("parse ISO WKB: %v", err)
+	}
+	if got := g.WKT(); got != "POINT Z (1 2 3)" {
+		t.Fatalf("ISO WKB: got %q", got)
+	}
+	if got := g.WKBHex(); got != iso {
+		t.Fatalf("ISO WKB round trip: got %s", got)
+	}
+
+	// 点数超出数据长度
+	if _, err := ParseWKBHex("010200000010000000"); err == nil {
+		t.Fatal("expected error for truncated WKB")
+	}
+}
+
+func TestTransformWKT(t *testing.T) {
+	want, _ := Transform(Position{116.397, 39.908}, WGS84, GCJ02)
+
+	out, err := Transform("POINT Z (116.397 39.908 50)", WGS84, GCJ02)
+	if err != nil {
+		t.Fatalf("transform WKT: %v", err)
+	}
+	g, err := ParseWKT(out)
+	if err != nil {
+		t.Fatalf("output is not WKT: %q", out)
+	}
+	pt := g.Points[0]
+	if !g.HasZ || !approxPos(Position{pt[0], pt[1]}, want, 1e-12) || pt[2] != 50 {
+		t.Fatalf("unexpected output %q", out)
+	}
+
+	// SRID 更新为目标坐标系的 EPSG 代码
+	out, err = Transform("SRID=4326;POINT (116.397 39.908)", WGS84, EPSG3857)
+	if err != nil {
+		t.Fatalf("transform EWKT: %v", err)
+	}
+	if !strings.HasPrefix(out, "SRID=3857;POINT (") {
+		t.Fatalf("unexpected SRID: %q", out)
+	}
+
+	if _, err := Transform("POINT (116.397", WGS84, GCJ02); GetErrorType(err) != ErrInvalidInput {
+		t.Fatalf("expected ErrInvalidInput, got %v", err)
+	}
+
+	// 不以几何类型开头的字符串按 JSON 解析并报告 JSON 错误
+	for _, bad := range []string{"hello", "null,", "true false", "Pointless (1 2)"} {
+		_, err := Transform(bad, WGS84, GCJ02)
+		if te, ok := err.(*TransformError); !ok || te.Type != ErrInvalidInput || te.Details["original_error"] == nil {
+			t.Fatalf("%q: expected JSON parse error, got %v", bad, err)
+		}
+	}
+}
+
+func TestTransformWKB(t *testing.T) {
+	g, _ := ParseWKT("LINESTRING M (116.397 39.908 1, 121.473 31.23 2)")
+	want, _ := Transform(Position{121.473, 31.23}, WGS84, BD09)
+
+	out, err := Transform(g.WKB(), WGS84, BD09)
+	if err != nil {
+		t.Fatalf("transform WKB: %v", err)
+	}
+	res, err := ParseWKB(out)
+	if err != nil {
+		t.Fatalf("output is not WKB: %v", err)
+	}
+	pt := res.Points[1]
+	if !res.HasM || !approxPos(Position{pt[0], pt[1]}, want, 1e-12) || pt[2] != 2 {
+		t.Fatalf("unexpected output %s", res.WKT())
+	}
+
+	hexOut, err := Transform(strings.ToLower(g.WKBHex()), WGS84, BD09)
+	if err != nil {
+		t.Fatalf("transform hex WKB: %v", err)
+	}
+	if hexOut != strings.ToLower(res.WKBHex()) {
+		t.Fatalf("hex output mismatch: %s", hexOut)
+	}
+	if !bytes.Equal(out, res.WKB()) {
+		t.Fatal("WKB output should be stable")
+	}
+}